@@ -2,10 +2,9 @@ package main
 
 import (
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
-	"path"
-	"strings"
 	"time"
 
 	"github.com/kdomanski/iso9660"
@@ -27,48 +26,22 @@ func main() {
 		log.Fatalf("failed to open image %s: %s", os.Args[1], err)
 	}
 
-	root, err := iso.RootDir()
-	if err != nil {
-		log.Fatalf("failed to open iso root %s: %s", os.Args[1], err)
-	}
-
-	if err := printEntries(root, []string{}); err != nil {
-		log.Fatal(err)
-	}
-}
+	err = fs.WalkDir(iso.FS(), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
 
-func printEntries(file *iso9660.File, parents []string) error {
-	if len(parents) > 1 && (file.Name() == "\x00" || file.Name() == "\x01") {
-		return nil
-	}
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", p, err)
+		}
 
-	printEntry(file, parents)
+		fmt.Printf("%q age: %v bytes: %v, dir: %v\n",
+			p, time.Since(info.ModTime()).Round(time.Second), info.Size(), d.IsDir())
 
-	if !file.IsDir() {
 		return nil
-	}
-
-	childs, err := file.GetChildren()
+	})
 	if err != nil {
-		return fmt.Errorf("getting children for %s: %w", file.Name(), err)
-	}
-
-	for _, entry := range childs {
-		if err := printEntries(entry, append(parents, file.Name())); err != nil {
-			return err
-		}
+		log.Fatal(err)
 	}
-
-	return nil
-}
-
-func printEntry(file *iso9660.File, parents []string) {
-	path := path.Join(append(parents, file.Name())...)
-	path = strings.ReplaceAll(path, "\x00", ".")
-	path = strings.ReplaceAll(path, "\x01", "..")
-
-	fmt.Printf("%q age: %v bytes: %v, me: %v\n",
-		path,
-		time.Since(file.ModTime()).Round(time.Second),
-		file.Size(), file.HasMultiExtent())
 }
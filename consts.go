@@ -0,0 +1,27 @@
+package iso9660
+
+// Logical sector size used throughout ISO9660 images.
+const sectorSize = 2048
+
+// systemAreaSectors is the number of sectors reserved for
+// platform-specific boot code before the volume descriptor set begins.
+const systemAreaSectors = 16
+
+// Volume descriptor types, as defined by ECMA-119 section 8.1.
+const (
+	volumeTypeBootRecord    byte = 0
+	volumeTypePrimary       byte = 1
+	volumeTypeSupplementary byte = 2
+	volumeTypePartition     byte = 3
+	volumeTypeTerminator    byte = 255
+)
+
+// Directory record file flags, as defined by ECMA-119 section 9.1.6.
+const (
+	dirFlagHidden      byte = 1 << 0
+	dirFlagDir         byte = 1 << 1
+	dirFlagAssociated  byte = 1 << 2
+	dirFlagRecord      byte = 1 << 3
+	dirFlagProtection  byte = 1 << 4
+	dirFlagMultiExtent byte = 1 << 7
+)
@@ -0,0 +1,119 @@
+package iso9660
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errZeroRecordLength is returned internally by UnmarshalBinary to signal
+// that a directory record length of zero was encountered, which marks the
+// unused tail of a directory extent's final sector rather than an error.
+var errZeroRecordLength = errors.New("iso9660: zero-length directory record")
+
+// DirectoryEntry is a single ISO9660 directory record, as defined by
+// ECMA-119 section 9.1.
+type DirectoryEntry struct {
+	ExtendedAttributeLength byte
+	ExtentLocation          uint32
+	ExtentLength            uint32
+	RecordingDateTime       RecordingTimestamp
+	FileFlags               byte
+	FileUnitSize            byte
+	InterleaveGapSize       byte
+	VolumeSequenceNumber    uint16
+	Identifier              string
+	SystemUse               []byte
+}
+
+// MarshalBinary encodes the directory record into its on-disk
+// representation, including the identifier and any trailing system use
+// area, padded to an even number of bytes as required by ECMA-119.
+func (de DirectoryEntry) MarshalBinary() ([]byte, error) {
+	idLen := len(de.Identifier)
+	if idLen > 255 {
+		return nil, fmt.Errorf("iso9660: identifier %q too long", de.Identifier)
+	}
+
+	pad := 0
+	if (33+idLen)%2 != 0 {
+		pad = 1
+	}
+
+	sysUseOffset := 33 + idLen + pad
+	recordLen := sysUseOffset + len(de.SystemUse)
+	if recordLen%2 != 0 {
+		recordLen++ // trailing zero byte after an odd-length system use area
+	}
+	if recordLen > 255 {
+		return nil, fmt.Errorf("iso9660: directory record for %q exceeds 255 bytes", de.Identifier)
+	}
+
+	buf := make([]byte, recordLen)
+	buf[0] = byte(recordLen)
+	buf[1] = de.ExtendedAttributeLength
+	WriteInt32LSBMSB(buf[2:10], int32(de.ExtentLocation))
+	WriteInt32LSBMSB(buf[10:18], int32(de.ExtentLength))
+
+	ts, err := de.RecordingDateTime.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(buf[18:25], ts)
+
+	buf[25] = de.FileFlags
+	buf[26] = de.FileUnitSize
+	buf[27] = de.InterleaveGapSize
+	WriteInt16LSBMSB(buf[28:32], int16(de.VolumeSequenceNumber))
+	buf[32] = byte(idLen)
+	copy(buf[33:33+idLen], de.Identifier)
+	copy(buf[sysUseOffset:], de.SystemUse)
+
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a single directory record from the start of
+// data, which must contain at least the remainder of the directory
+// extent's current sector. It returns errZeroRecordLength once it hits
+// the zero-filled padding that follows the last record in a sector.
+func (de *DirectoryEntry) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errZeroRecordLength
+	}
+
+	recordLen := int(data[0])
+	if recordLen == 0 {
+		return errZeroRecordLength
+	}
+	if recordLen < 34 || recordLen > len(data) {
+		return fmt.Errorf("iso9660: invalid directory record length %d", recordLen)
+	}
+
+	de.ExtendedAttributeLength = data[1]
+	de.ExtentLocation = uint32(ReadInt32LSBMSB(data[2:10]))
+	de.ExtentLength = uint32(ReadInt32LSBMSB(data[10:18]))
+	if err := de.RecordingDateTime.UnmarshalBinary(data[18:25]); err != nil {
+		return err
+	}
+	de.FileFlags = data[25]
+	de.FileUnitSize = data[26]
+	de.InterleaveGapSize = data[27]
+	de.VolumeSequenceNumber = uint16(ReadInt16LSBMSB(data[28:32]))
+
+	idLen := int(data[32])
+	if 33+idLen > recordLen {
+		return fmt.Errorf("iso9660: identifier length %d exceeds record length %d", idLen, recordLen)
+	}
+	de.Identifier = string(data[33 : 33+idLen])
+
+	sysUseOffset := 33 + idLen
+	if sysUseOffset%2 != 0 {
+		sysUseOffset++
+	}
+	if sysUseOffset < recordLen {
+		de.SystemUse = append([]byte(nil), data[sysUseOffset:recordLen]...)
+	} else {
+		de.SystemUse = nil
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package iso9660
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// elToritoBootSystemID is the Boot System Identifier a Boot Record Volume
+// Descriptor must carry for its Boot Record Volume Descriptor to identify
+// an El Torito boot catalog, per the El Torito specification section 2.0.
+const elToritoBootSystemID = "EL TORITO SPECIFICATION"
+
+// BootPlatform identifies the target platform of a boot catalog entry, as
+// carried by its Validation Entry or Section Header Entry.
+type BootPlatform byte
+
+// Boot catalog platform IDs, El Torito specification section 1.3.
+const (
+	BootPlatformX86     BootPlatform = 0x00
+	BootPlatformPowerPC BootPlatform = 0x01
+	BootPlatformMac     BootPlatform = 0x02
+	BootPlatformEFI     BootPlatform = 0xEF
+)
+
+// BootMediaType identifies how the BIOS should emulate a boot entry's
+// image, as carried by its Boot Media Type field.
+type BootMediaType byte
+
+// Boot media types, El Torito specification section 2.2.
+const (
+	BootMediaNoEmulation BootMediaType = 0
+	BootMediaFloppy1_2M  BootMediaType = 1
+	BootMediaFloppy1_44M BootMediaType = 2
+	BootMediaFloppy2_88M BootMediaType = 3
+	BootMediaHardDisk    BootMediaType = 4
+)
+
+// BootEntry describes a single bootable image referenced by an El Torito
+// boot catalog: either the catalog's Initial/Default Entry, or one
+// Section Entry of a Section Header used for hybrid (e.g. BIOS+UEFI)
+// images.
+type BootEntry struct {
+	Platform    BootPlatform
+	Bootable    bool
+	MediaType   BootMediaType
+	LoadSegment uint16
+	SectorCount uint16
+
+	image    *Image
+	imageLBA uint32
+}
+
+// Reader returns a reader over the entry's boot image bytes. Its length
+// is SectorCount virtual (512-byte) sectors, as defined by the
+// specification; when SectorCount is zero, it falls back to the length
+// implied by MediaType.
+func (e BootEntry) Reader() io.Reader {
+	length := int64(e.SectorCount) * 512
+	if length == 0 {
+		length = bootMediaImpliedBytes(e.MediaType)
+	}
+	return io.NewSectionReader(e.image.reader, int64(e.imageLBA)*sectorSize, length)
+}
+
+func bootMediaImpliedBytes(mt BootMediaType) int64 {
+	switch mt {
+	case BootMediaFloppy1_2M:
+		return 1200 * 1024
+	case BootMediaFloppy1_44M:
+		return 1440 * 1024
+	case BootMediaFloppy2_88M:
+		return 2880 * 1024
+	default:
+		return sectorSize
+	}
+}
+
+// bootCatalogValidationEntry is the first, 32-byte, entry of every El
+// Torito boot catalog.
+type bootCatalogValidationEntry struct {
+	PlatformID BootPlatform
+}
+
+func unmarshalValidationEntry(data []byte) (*bootCatalogValidationEntry, error) {
+	if len(data) < 32 {
+		return nil, fmt.Errorf("iso9660: boot catalog validation entry too short: %d bytes", len(data))
+	}
+	if data[0] != 0x01 {
+		return nil, fmt.Errorf("iso9660: invalid boot catalog validation entry header ID 0x%02x", data[0])
+	}
+	if data[30] != 0x55 || data[31] != 0xAA {
+		return nil, fmt.Errorf("iso9660: invalid boot catalog validation entry key bytes")
+	}
+
+	var sum uint16
+	for i := 0; i < 32; i += 2 {
+		sum += binary.LittleEndian.Uint16(data[i : i+2])
+	}
+	if sum != 0 {
+		return nil, fmt.Errorf("iso9660: boot catalog validation entry checksum mismatch")
+	}
+
+	return &bootCatalogValidationEntry{PlatformID: BootPlatform(data[1])}, nil
+}
+
+// unmarshalBootEntry decodes a 32-byte Initial/Default Entry or Section
+// Entry, both of which share the same layout.
+func unmarshalBootEntry(data []byte, platform BootPlatform) (BootEntry, error) {
+	if len(data) < 32 {
+		return BootEntry{}, fmt.Errorf("iso9660: boot catalog entry too short: %d bytes", len(data))
+	}
+
+	return BootEntry{
+		Platform:    platform,
+		Bootable:    data[0] == 0x88,
+		MediaType:   BootMediaType(data[1] & 0x0F),
+		LoadSegment: binary.LittleEndian.Uint16(data[2:4]),
+		SectorCount: binary.LittleEndian.Uint16(data[6:8]),
+		imageLBA:    binary.LittleEndian.Uint32(data[8:12]),
+	}, nil
+}
+
+// bootSectionHeaderIndicator values, El Torito specification section 2.4.
+const (
+	bootSectionHeaderMore  byte = 0x90
+	bootSectionHeaderFinal byte = 0x91
+)
+
+// BootCatalog parses and returns the image's El Torito boot catalog: the
+// Initial/Default Entry, followed by every Section Entry from any Section
+// Headers that follow it (used for hybrid BIOS+UEFI images). It returns
+// (nil, nil) when the image has no El Torito Boot Record.
+func (i *Image) BootCatalog() ([]BootEntry, error) {
+	if !i.hasBootRecord {
+		return nil, nil
+	}
+
+	sector := make([]byte, sectorSize)
+	if _, err := i.reader.ReadAt(sector, int64(i.bootCatalogLBA)*sectorSize); err != nil {
+		return nil, fmt.Errorf("iso9660: reading boot catalog at sector %d: %w", i.bootCatalogLBA, err)
+	}
+
+	validation, err := unmarshalValidationEntry(sector[0:32])
+	if err != nil {
+		return nil, err
+	}
+
+	initial, err := unmarshalBootEntry(sector[32:64], validation.PlatformID)
+	if err != nil {
+		return nil, err
+	}
+	initial.image = i
+
+	entries := []BootEntry{initial}
+
+	offset := 64
+	for offset+32 <= len(sector) {
+		header := sector[offset : offset+32]
+		indicator := header[0]
+		if indicator != bootSectionHeaderMore && indicator != bootSectionHeaderFinal {
+			break // no (more) section headers
+		}
+
+		platform := BootPlatform(header[1])
+		count := int(binary.LittleEndian.Uint16(header[2:4]))
+		offset += 32
+
+		for j := 0; j < count && offset+32 <= len(sector); j++ {
+			entry, err := unmarshalBootEntry(sector[offset:offset+32], platform)
+			if err != nil {
+				return nil, err
+			}
+			entry.image = i
+			entries = append(entries, entry)
+			offset += 32
+		}
+
+		if indicator == bootSectionHeaderFinal {
+			break
+		}
+	}
+
+	return entries, nil
+}
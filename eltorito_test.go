@@ -0,0 +1,170 @@
+//go:build !integration
+// +build !integration
+
+package iso9660
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTestISOWithElTorito extends buildTestISO's layout with a Boot
+// Record Volume Descriptor and an El Torito boot catalog:
+//
+//	sector 16: boot record volume descriptor
+//	sector 17: primary volume descriptor
+//	sector 18: terminator volume descriptor
+//	sector 19: root directory extent
+//	sector 20: boot catalog
+//	sector 21: x86 boot image
+//	sector 22: EFI boot image (hybrid images only)
+func buildTestISOWithElTorito(t *testing.T, hybrid bool) []byte {
+	t.Helper()
+
+	totalSectors := 22
+	if hybrid {
+		totalSectors = 23
+	}
+
+	img := make([]byte, totalSectors*int(sectorSize))
+
+	bootRecordSector := img[16*sectorSize : 17*sectorSize]
+	bootRecordSector[0] = volumeTypeBootRecord
+	copy(bootRecordSector[1:6], "CD001")
+	bootRecordSector[6] = 1
+	copy(bootRecordSector[7:39], elToritoBootSystemID)
+	binary.LittleEndian.PutUint32(bootRecordSector[71:75], 20)
+
+	rootDE := DirectoryEntry{ExtentLocation: 19, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})}
+	rootDEBytes, err := rootDE.MarshalBinary()
+	require.NoError(t, err)
+
+	pvdSector := img[17*sectorSize : 18*sectorSize]
+	pvdSector[0] = volumeTypePrimary
+	copy(pvdSector[1:6], "CD001")
+	pvdSector[6] = 1
+	copy(pvdSector[40:72], MarshalString("TESTISO", 32))
+	WriteInt32LSBMSB(pvdSector[80:88], int32(totalSectors))
+	WriteInt16LSBMSB(pvdSector[128:132], int16(sectorSize))
+	copy(pvdSector[156:190], rootDEBytes)
+	pvdSector[881] = 1
+
+	termSector := img[18*sectorSize : 19*sectorSize]
+	termSector[0] = volumeTypeTerminator
+	copy(termSector[1:6], "CD001")
+	termSector[6] = 1
+
+	rootDirSector := img[19*sectorSize : 20*sectorSize]
+	offset := 0
+	for _, de := range []DirectoryEntry{
+		{ExtentLocation: 19, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})},
+		{ExtentLocation: 19, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})},
+	} {
+		data, err := de.MarshalBinary()
+		require.NoError(t, err)
+		copy(rootDirSector[offset:], data)
+		offset += len(data)
+	}
+
+	catalog := img[20*sectorSize : 21*sectorSize]
+
+	validation := catalog[0:32]
+	validation[0] = 0x01                  // header ID
+	validation[1] = byte(BootPlatformX86) // platform ID
+	validation[30], validation[31] = 0x55, 0xAA
+	binary.LittleEndian.PutUint16(validation[28:30], validationChecksum(validation))
+
+	initial := catalog[32:64]
+	initial[0] = 0x88 // bootable
+	initial[1] = byte(BootMediaNoEmulation)
+	binary.LittleEndian.PutUint16(initial[6:8], 4) // sector count
+	binary.LittleEndian.PutUint32(initial[8:12], 21)
+
+	copy(img[21*sectorSize:], []byte("x86 boot image.."))
+
+	if hybrid {
+		header := catalog[64:96]
+		header[0] = bootSectionHeaderFinal
+		header[1] = byte(BootPlatformEFI)
+		binary.LittleEndian.PutUint16(header[2:4], 1)
+
+		section := catalog[96:128]
+		section[0] = 0x88
+		section[1] = byte(BootMediaNoEmulation)
+		binary.LittleEndian.PutUint16(section[6:8], 2)
+		binary.LittleEndian.PutUint32(section[8:12], 22)
+
+		copy(img[22*sectorSize:], []byte("EFI image."))
+	}
+
+	return img
+}
+
+func validationChecksum(entry []byte) uint16 {
+	var sum uint16
+	for i := 0; i < 32; i += 2 {
+		if i == 28 {
+			continue
+		}
+		sum += binary.LittleEndian.Uint16(entry[i : i+2])
+	}
+	return -sum
+}
+
+func TestBootCatalogNoEmulation(t *testing.T) {
+	img := buildTestISOWithElTorito(t, false)
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	entries, err := image.BootCatalog()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	e := entries[0]
+	assert.Equal(t, BootPlatformX86, e.Platform)
+	assert.True(t, e.Bootable)
+	assert.Equal(t, BootMediaNoEmulation, e.MediaType)
+	assert.EqualValues(t, 4, e.SectorCount)
+
+	data, err := io.ReadAll(e.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, "x86 boot image..", string(data[:16]))
+}
+
+func TestBootCatalogHybridSections(t *testing.T) {
+	img := buildTestISOWithElTorito(t, true)
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	entries, err := image.BootCatalog()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, BootPlatformX86, entries[0].Platform)
+	assert.Equal(t, BootPlatformEFI, entries[1].Platform)
+
+	data, err := io.ReadAll(entries[1].Reader())
+	require.NoError(t, err)
+	assert.Equal(t, "EFI image.", string(data[:10]))
+}
+
+func TestNoBootCatalogWhenAbsent(t *testing.T) {
+	img := buildTestISO(t, []DirectoryEntry{
+		{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})},
+		{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})},
+	}, nil)
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	entries, err := image.BootCatalog()
+	require.NoError(t, err)
+	assert.Nil(t, entries)
+}
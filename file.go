@@ -0,0 +1,358 @@
+package iso9660
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// extentRun describes one contiguous run of sectors backing part of a
+// (possibly multi-extent) file.
+type extentRun struct {
+	location uint32
+	length   uint32
+}
+
+// File represents a single entry - file or directory - in an ISO9660
+// directory tree.
+type File struct {
+	image *Image
+	de    DirectoryEntry
+
+	// joliet is true when this File was reached through the Joliet
+	// Supplementary Volume Descriptor tree rather than the primary one.
+	joliet bool
+
+	// extents holds the individual extent runs that make up a file split
+	// across multiple directory records. It is nil for files that fit in
+	// a single record, so that HasMultiExtent reports false for them.
+	extents []extentRun
+
+	// rr holds this entry's Rock Ridge metadata, or nil if the image has
+	// no Rock Ridge extensions or this particular entry carried none.
+	rr *rockRidgeInfo
+}
+
+func newFile(image *Image, de DirectoryEntry, joliet bool) *File {
+	return &File{image: image, de: de, joliet: joliet}
+}
+
+// Name returns the entry's name. A Rock Ridge "NM" alternate name, if
+// present, always wins; otherwise Joliet UTF-16BE identifiers are decoded
+// when this File belongs to the Joliet tree, and any ";<version>" suffix
+// is stripped.
+func (f *File) Name() string {
+	if f.rr != nil && f.rr.hasName {
+		return f.rr.name
+	}
+
+	name := f.de.Identifier
+	if f.joliet {
+		name = decodeUTF16BE([]byte(name))
+	}
+
+	if f.IsDir() {
+		return name
+	}
+
+	if idx := strings.LastIndexByte(name, ';'); idx >= 0 {
+		name = name[:idx]
+	}
+
+	return name
+}
+
+// IsDir reports whether the entry is a directory.
+func (f *File) IsDir() bool {
+	return f.de.FileFlags&dirFlagDir != 0
+}
+
+// Size returns the total size in bytes of the file's content, summed
+// across all of its extents.
+func (f *File) Size() int64 {
+	if len(f.extents) > 0 {
+		var total int64
+		for _, e := range f.extents {
+			total += int64(e.length)
+		}
+		return total
+	}
+
+	return int64(f.de.ExtentLength)
+}
+
+// ModTime returns the entry's modification time: the Rock Ridge "TF"
+// modify timestamp when present, otherwise the directory record's
+// recording date and time.
+func (f *File) ModTime() time.Time {
+	if f.rr != nil && f.rr.hasMTime {
+		return time.Unix(f.rr.modTime, 0).UTC()
+	}
+	return f.de.RecordingDateTime.Time()
+}
+
+// Mode returns the entry's POSIX file mode, taken from a Rock Ridge "PX"
+// entry when present. Without Rock Ridge, a minimal mode is synthesized
+// from IsDir, since ISO9660 itself carries no permission bits.
+func (f *File) Mode() os.FileMode {
+	if f.rr != nil && f.rr.hasMode {
+		return f.rr.mode
+	}
+	if f.IsDir() {
+		return os.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// Uid returns the owning user ID from a Rock Ridge "PX" entry, or 0 when
+// Rock Ridge is not present.
+func (f *File) Uid() uint32 {
+	if f.rr != nil {
+		return f.rr.uid
+	}
+	return 0
+}
+
+// Gid returns the owning group ID from a Rock Ridge "PX" entry, or 0 when
+// Rock Ridge is not present.
+func (f *File) Gid() uint32 {
+	if f.rr != nil {
+		return f.rr.gid
+	}
+	return 0
+}
+
+// IsSymlink reports whether Rock Ridge marks this entry as a symbolic
+// link via an "SL" entry.
+func (f *File) IsSymlink() bool {
+	return f.rr != nil && f.rr.isSymlink
+}
+
+// Readlink returns the target of a Rock Ridge symbolic link.
+func (f *File) Readlink() (string, error) {
+	if f.rr == nil || !f.rr.isSymlink {
+		return "", fmt.Errorf("iso9660: %q is not a symlink", f.Name())
+	}
+	return f.rr.symlink, nil
+}
+
+// HasMultiExtent reports whether the file's content is split across more
+// than one directory record / extent.
+func (f *File) HasMultiExtent() bool {
+	return len(f.extents) > 0
+}
+
+// runs returns the extent runs backing this file's content, synthesizing
+// a single-element slice from the directory entry itself when the file
+// was not split across multiple records.
+func (f *File) runs() []extentRun {
+	if len(f.extents) > 0 {
+		return f.extents
+	}
+	return []extentRun{{location: f.de.ExtentLocation, length: f.de.ExtentLength}}
+}
+
+// Reader returns a reader over the file's content, transparently
+// concatenating multiple extents where present.
+func (f *File) Reader() io.Reader {
+	runs := f.runs()
+	readers := make([]io.Reader, len(runs))
+	for i, r := range runs {
+		readers[i] = io.NewSectionReader(f.image.reader, int64(r.location)*sectorSize, int64(r.length))
+	}
+	return io.MultiReader(readers...)
+}
+
+// fileReaderAt implements io.ReaderAt over a File's content by binary-
+// searching the (possibly multiple) extent runs for the one containing a
+// given logical offset, translating it to the corresponding position in
+// the underlying image, and looping across runs when a read straddles an
+// extent boundary.
+type fileReaderAt struct {
+	image   *Image
+	runs    []extentRun
+	offsets []int64 // cumulative logical offset at which each run starts
+	size    int64
+}
+
+func newFileReaderAt(f *File) *fileReaderAt {
+	runs := f.runs()
+	r := &fileReaderAt{image: f.image, runs: runs, offsets: make([]int64, len(runs))}
+
+	var total int64
+	for i, run := range runs {
+		r.offsets[i] = total
+		total += int64(run.length)
+	}
+	r.size = total
+
+	return r
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *fileReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("iso9660: ReadAt: negative offset")
+	}
+	if off >= r.size {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+
+	// Binary search for the last run whose start offset is <= off.
+	idx := sort.Search(len(r.offsets), func(i int) bool { return r.offsets[i] > off }) - 1
+
+	n := 0
+	for idx < len(r.runs) && n < len(p) {
+		run := r.runs[idx]
+		intra := off - r.offsets[idx]
+
+		want := int64(len(p) - n)
+		if avail := int64(run.length) - intra; want > avail {
+			want = avail
+		}
+
+		read, err := r.image.reader.ReadAt(p[n:int64(n)+want], int64(run.location)*sectorSize+intra)
+		n += read
+		off += int64(read)
+
+		if err != nil {
+			return n, err
+		}
+		if int64(read) < want {
+			return n, io.ErrUnexpectedEOF
+		}
+
+		idx++
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// ReaderAt returns an io.ReaderAt over the file's content, mapping
+// absolute offsets directly through its extents to the underlying image.
+// Unlike Reader, it supports random access without first reading (or
+// seeking past) everything before the desired offset, making it suitable
+// for zero-copy access into large or multi-extent files.
+func (f *File) ReaderAt() io.ReaderAt {
+	return newFileReaderAt(f)
+}
+
+// SectionReader returns an io.SectionReader over the n bytes of the
+// file's content starting at offset off, built on ReaderAt.
+func (f *File) SectionReader(off, n int64) *io.SectionReader {
+	return io.NewSectionReader(f.ReaderAt(), off, n)
+}
+
+// GetChildren reads and returns the entries contained within a directory
+// file. Consecutive directory records sharing an identifier (the
+// multi-extent case) are merged into a single File, and the special "."
+// and ".." self/parent entries are omitted, matching the convention of
+// the standard library's fs.ReadDir.
+func (f *File) GetChildren() ([]*File, error) {
+	if !f.IsDir() {
+		return nil, fmt.Errorf("iso9660: %q is not a directory", f.Name())
+	}
+
+	var children []*File
+
+	for _, run := range f.runs() {
+		sector := make([]byte, run.length)
+		if _, err := f.image.reader.ReadAt(sector, int64(run.location)*sectorSize); err != nil {
+			return nil, fmt.Errorf("iso9660: reading directory extent at sector %d: %w", run.location, err)
+		}
+
+		offset := 0
+		for offset < len(sector) {
+			var de DirectoryEntry
+			if err := de.UnmarshalBinary(sector[offset:]); err != nil {
+				if err == errZeroRecordLength {
+					break // rest of the sector is unused padding
+				}
+				return nil, err
+			}
+			offset += int(sector[offset])
+
+			if isDotEntry(de.Identifier) {
+				continue
+			}
+
+			if len(children) > 0 {
+				last := children[len(children)-1]
+				if last.de.FileFlags&dirFlagMultiExtent != 0 && last.de.Identifier == de.Identifier {
+					last.appendExtent(de)
+					continue
+				}
+			}
+
+			child := newFile(f.image, de, f.joliet)
+
+			if len(de.SystemUse) > 0 && f.image.hasRockRidgeExtension() {
+				rr := &rockRidgeInfo{}
+				if err := parseSystemUse(f.image.readAt, de.SystemUse, rr); err != nil {
+					return nil, err
+				}
+				child.rr = rr
+
+				if rr.hidden {
+					continue // the real copy of a directory relocated elsewhere by "CL"
+				}
+				if rr.relocated && rr.relocLoc != 0 {
+					length, err := f.relocatedDirLength(rr.relocLoc)
+					if err != nil {
+						return nil, err
+					}
+					child.de.ExtentLocation = rr.relocLoc
+					child.de.ExtentLength = length
+				}
+			}
+
+			children = append(children, child)
+		}
+	}
+
+	return children, nil
+}
+
+// relocatedDirLength reads the "." entry of the directory at extent
+// location loc and returns its own ExtentLength - the authoritative size
+// of a directory relocated elsewhere via Rock Ridge's "CL" entry, which
+// its placeholder entry in the physical parent does not carry.
+func (f *File) relocatedDirLength(loc uint32) (uint32, error) {
+	sector := make([]byte, sectorSize)
+	if _, err := f.image.reader.ReadAt(sector, int64(loc)*sectorSize); err != nil {
+		return 0, fmt.Errorf("iso9660: reading relocated directory at sector %d: %w", loc, err)
+	}
+
+	var dot DirectoryEntry
+	if err := dot.UnmarshalBinary(sector); err != nil {
+		return 0, fmt.Errorf("iso9660: reading relocated directory's \".\" entry at sector %d: %w", loc, err)
+	}
+
+	return dot.ExtentLength, nil
+}
+
+// appendExtent folds an additional directory record for the same file
+// into f's extents, tracking the latest record's flags so that GetChildren
+// stops merging once the final record (without dirFlagMultiExtent) is in.
+func (f *File) appendExtent(de DirectoryEntry) {
+	if len(f.extents) == 0 {
+		f.extents = []extentRun{{location: f.de.ExtentLocation, length: f.de.ExtentLength}}
+	}
+	f.extents = append(f.extents, extentRun{location: de.ExtentLocation, length: de.ExtentLength})
+	f.de.FileFlags = de.FileFlags
+}
+
+// isDotEntry reports whether identifier is the special "." or ".." entry
+// ECMA-119 requires at the start of every directory extent.
+func isDotEntry(identifier string) bool {
+	return len(identifier) == 1 && (identifier[0] == 0 || identifier[0] == 1)
+}
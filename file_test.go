@@ -0,0 +1,99 @@
+//go:build !integration
+// +build !integration
+
+package iso9660
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileReaderAtSingleExtent(t *testing.T) {
+	dotEntry := DirectoryEntry{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})}
+	dotDotEntry := DirectoryEntry{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})}
+	fileEntry := DirectoryEntry{ExtentLocation: 19, ExtentLength: 11, Identifier: "HELLO.TXT;1"}
+
+	img := buildTestISO(t, []DirectoryEntry{dotEntry, dotDotEntry, fileEntry}, map[int32][]byte{
+		19: []byte("hello world"),
+	})
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	root, err := image.RootDir()
+	require.NoError(t, err)
+
+	children, err := root.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+
+	f := children[0]
+	assert.Nil(t, f.extents) // single-extent files keep extents nil
+
+	buf := make([]byte, 5)
+	n, err := f.ReaderAt().ReadAt(buf, 6)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(buf[:n]))
+
+	sr := f.SectionReader(0, 5)
+	data := make([]byte, 5)
+	_, err = sr.Read(data)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestFileReaderAtStraddlesExtents(t *testing.T) {
+	dotEntry := DirectoryEntry{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})}
+	dotDotEntry := DirectoryEntry{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})}
+
+	me1 := DirectoryEntry{ExtentLocation: 19, ExtentLength: 100, FileFlags: dirFlagMultiExtent, Identifier: "BIGFILE.BIN;1"}
+	me2 := DirectoryEntry{ExtentLocation: 20, ExtentLength: 100, FileFlags: dirFlagMultiExtent, Identifier: "BIGFILE.BIN;1"}
+	me3 := DirectoryEntry{ExtentLocation: 21, ExtentLength: 50, Identifier: "BIGFILE.BIN;1"}
+
+	extent1 := bytes.Repeat([]byte("A"), 100)
+	extent2 := bytes.Repeat([]byte("B"), 100)
+	extent3 := bytes.Repeat([]byte("C"), 50)
+
+	img := buildTestISO(t, []DirectoryEntry{dotEntry, dotDotEntry, me1, me2, me3}, map[int32][]byte{
+		19: extent1,
+		20: extent2,
+		21: extent3,
+	})
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	root, err := image.RootDir()
+	require.NoError(t, err)
+
+	children, err := root.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+
+	f := children[0]
+	require.Len(t, f.extents, 3)
+
+	ra := f.ReaderAt()
+
+	// A read straddling the boundary between extent 1 and extent 2.
+	buf := make([]byte, 10)
+	n, err := ra.ReadAt(buf, 95)
+	require.NoError(t, err)
+	assert.Equal(t, "AAAAABBBBB", string(buf[:n]))
+
+	// A read straddling extent 2 and the final (shorter) extent 3.
+	buf = make([]byte, 10)
+	n, err = ra.ReadAt(buf, 195)
+	require.NoError(t, err)
+	assert.Equal(t, "BBBBBCCCCC", string(buf[:n]))
+
+	sr := f.SectionReader(90, 160)
+	data := make([]byte, 160)
+	_, err = sr.Read(data)
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte("A"), 10), data[:10])
+	assert.Equal(t, bytes.Repeat([]byte("C"), 10), data[150:])
+}
@@ -0,0 +1,282 @@
+package iso9660
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FS returns an io/fs.FS view onto the image's directory tree: the
+// primary ISO9660 tree when the image carries Rock Ridge extensions -
+// real-world Rock Ridge images put NM/PX/TF/SL data on the primary tree's
+// directory records only, never the Joliet ones - otherwise the Joliet
+// tree when the image has one, otherwise the primary tree. Paths are
+// forward-slash separated and cleaned, the ";<version>" suffix ISO9660
+// appends to file names is stripped, and the "." / ".." self-reference
+// entries are omitted - all per the conventions File and GetChildren
+// already follow.
+func (i *Image) FS() fs.FS {
+	if i.hasRockRidgeExtension() {
+		root, _ := i.RootDir() // only fails if OpenImage itself would have
+		return &isoFS{root: root}
+	}
+
+	if root, err := i.JolietRoot(); err == nil && root != nil {
+		return &isoFS{root: root}
+	}
+
+	root, _ := i.RootDir() // only fails if OpenImage itself would have
+	return &isoFS{root: root}
+}
+
+type isoFS struct {
+	root *File
+}
+
+var (
+	_ fs.FS         = (*isoFS)(nil)
+	_ fs.ReadDirFS  = (*isoFS)(nil)
+	_ fs.StatFS     = (*isoFS)(nil)
+	_ fs.ReadFileFS = (*isoFS)(nil)
+	_ fs.GlobFS     = (*isoFS)(nil)
+	_ fs.SubFS      = (*isoFS)(nil)
+)
+
+// resolve walks name (a cleaned, slash-separated fs.FS path) from the
+// filesystem's root down to the File it names.
+func (ifs *isoFS) resolve(op, name string) (*File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	f := ifs.root
+	if name == "." {
+		return f, nil
+	}
+
+	for _, part := range strings.Split(name, "/") {
+		if !f.IsDir() {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+
+		children, err := f.GetChildren()
+		if err != nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+
+		next := findChild(children, part)
+		if next == nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+		}
+		f = next
+	}
+
+	return f, nil
+}
+
+func findChild(children []*File, name string) *File {
+	for _, c := range children {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Open implements fs.FS.
+func (ifs *isoFS) Open(name string) (fs.File, error) {
+	f, err := ifs.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.IsDir() {
+		return &isoDir{file: f, name: name}, nil
+	}
+
+	return &isoOpenFile{
+		name: name,
+		file: f,
+		sr:   io.NewSectionReader(newFileReaderAt(f), 0, f.Size()),
+	}, nil
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (ifs *isoFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	f, err := ifs.resolve("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !f.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fmt.Errorf("not a directory")}
+	}
+
+	children, err := f.GetChildren()
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	return sortedDirEntries(children), nil
+}
+
+func sortedDirEntries(children []*File) []fs.DirEntry {
+	entries := make([]fs.DirEntry, len(children))
+	for i, c := range children {
+		entries[i] = isoDirEntry{c}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries
+}
+
+// Stat implements fs.StatFS.
+func (ifs *isoFS) Stat(name string) (fs.FileInfo, error) {
+	f, err := ifs.resolve("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return isoFileInfo{f}, nil
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (ifs *isoFS) ReadFile(name string) ([]byte, error) {
+	f, err := ifs.resolve("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	if f.IsDir() {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fmt.Errorf("is a directory")}
+	}
+	return io.ReadAll(f.Reader())
+}
+
+// Glob implements fs.GlobFS. It walks the whole tree and matches each
+// path against pattern, which is adequate for the modestly sized
+// directory trees ISO images tend to hold.
+func (ifs *isoFS) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	err := fs.WalkDir(ifs, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ok, _ := path.Match(pattern, p); ok {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+
+	return matches, err
+}
+
+// Sub implements fs.SubFS.
+func (ifs *isoFS) Sub(dir string) (fs.FS, error) {
+	if dir == "." {
+		return ifs, nil
+	}
+
+	f, err := ifs.resolve("sub", dir)
+	if err != nil {
+		return nil, err
+	}
+	if !f.IsDir() {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fmt.Errorf("not a directory")}
+	}
+
+	return &isoFS{root: f}, nil
+}
+
+// isoFileInfo adapts a *File to fs.FileInfo.
+type isoFileInfo struct {
+	f *File
+}
+
+func (fi isoFileInfo) Name() string       { return fi.f.Name() }
+func (fi isoFileInfo) Size() int64        { return fi.f.Size() }
+func (fi isoFileInfo) Mode() fs.FileMode  { return fi.f.Mode() }
+func (fi isoFileInfo) ModTime() time.Time { return fi.f.ModTime() }
+func (fi isoFileInfo) IsDir() bool        { return fi.f.IsDir() }
+func (fi isoFileInfo) Sys() any           { return fi.f }
+
+// isoDirEntry adapts a *File to fs.DirEntry.
+type isoDirEntry struct {
+	f *File
+}
+
+func (e isoDirEntry) Name() string               { return e.f.Name() }
+func (e isoDirEntry) IsDir() bool                { return e.f.IsDir() }
+func (e isoDirEntry) Type() fs.FileMode          { return e.f.Mode().Type() }
+func (e isoDirEntry) Info() (fs.FileInfo, error) { return isoFileInfo{e.f}, nil }
+
+// isoOpenFile is the fs.File returned for a regular file, implementing
+// io.ReaderAt and io.Seeker on top of the file's (possibly multi-extent)
+// backing extents.
+type isoOpenFile struct {
+	name string
+	file *File
+	sr   *io.SectionReader
+}
+
+var (
+	_ fs.File     = (*isoOpenFile)(nil)
+	_ io.ReaderAt = (*isoOpenFile)(nil)
+	_ io.Seeker   = (*isoOpenFile)(nil)
+)
+
+func (of *isoOpenFile) Stat() (fs.FileInfo, error)                   { return isoFileInfo{of.file}, nil }
+func (of *isoOpenFile) Read(p []byte) (int, error)                   { return of.sr.Read(p) }
+func (of *isoOpenFile) ReadAt(p []byte, off int64) (int, error)      { return of.sr.ReadAt(p, off) }
+func (of *isoOpenFile) Seek(offset int64, whence int) (int64, error) { return of.sr.Seek(offset, whence) }
+func (of *isoOpenFile) Close() error                                 { return nil }
+
+// isoDir is the fs.ReadDirFile returned for a directory.
+type isoDir struct {
+	file    *File
+	name    string
+	entries []fs.DirEntry
+	pos     int
+}
+
+var _ fs.ReadDirFile = (*isoDir)(nil)
+
+func (d *isoDir) Stat() (fs.FileInfo, error) { return isoFileInfo{d.file}, nil }
+func (d *isoDir) Close() error               { return nil }
+
+func (d *isoDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fmt.Errorf("is a directory")}
+}
+
+func (d *isoDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.entries == nil {
+		children, err := d.file.GetChildren()
+		if err != nil {
+			return nil, err
+		}
+		d.entries = sortedDirEntries(children)
+	}
+
+	if n <= 0 {
+		rest := d.entries[d.pos:]
+		d.pos = len(d.entries)
+		return rest, nil
+	}
+
+	if d.pos >= len(d.entries) {
+		return nil, io.EOF
+	}
+
+	end := d.pos + n
+	if end > len(d.entries) {
+		end = len(d.entries)
+	}
+	out := d.entries[d.pos:end]
+	d.pos = end
+
+	return out, nil
+}
@@ -0,0 +1,179 @@
+//go:build !integration
+// +build !integration
+
+package iso9660
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildFSTestISO(t *testing.T) []byte {
+	t.Helper()
+
+	dotEntry := DirectoryEntry{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})}
+	dotDotEntry := DirectoryEntry{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})}
+	subdir := DirectoryEntry{ExtentLocation: 20, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: "SUB"}
+	readme := DirectoryEntry{ExtentLocation: 19, ExtentLength: 5, FileFlags: 0, Identifier: "README.TXT;1"}
+
+	img := buildTestISO(t, []DirectoryEntry{dotEntry, dotDotEntry, subdir, readme}, map[int32][]byte{
+		19: []byte("hello"),
+	})
+
+	// Extend the image with SUB's own directory extent at sector 20.
+	subDotEntry := DirectoryEntry{ExtentLocation: 20, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})}
+	subDotDotEntry := DirectoryEntry{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})}
+	nested := DirectoryEntry{ExtentLocation: 21, ExtentLength: 6, FileFlags: 0, Identifier: "INNER.TXT;1"}
+
+	if need := 22 * int(sectorSize); len(img) < need {
+		img = append(img, make([]byte, need-len(img))...)
+	}
+
+	offset := 20 * int(sectorSize)
+	for _, de := range []DirectoryEntry{subDotEntry, subDotDotEntry, nested} {
+		data, err := de.MarshalBinary()
+		require.NoError(t, err)
+		copy(img[offset:], data)
+		offset += len(data)
+	}
+	copy(img[21*int(sectorSize):], []byte("inner!"))
+
+	return img
+}
+
+func TestFSBasics(t *testing.T) {
+	img := buildFSTestISO(t)
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	fsys := image.FS()
+
+	data, err := fs.ReadFile(fsys, "README.TXT")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	data, err = fs.ReadFile(fsys, "SUB/INNER.TXT")
+	require.NoError(t, err)
+	assert.Equal(t, "inner!", string(data))
+
+	entries, err := fs.ReadDir(fsys, ".")
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{"README.TXT", "SUB"}, names)
+
+	var walked []string
+	require.NoError(t, fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, p)
+		return nil
+	}))
+	assert.ElementsMatch(t, []string{".", "README.TXT", "SUB", "SUB/INNER.TXT"}, walked)
+
+	matches, err := fs.Glob(fsys, "*.TXT")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.TXT"}, matches)
+
+	sub, err := fsys.(fs.SubFS).Sub("SUB")
+	require.NoError(t, err)
+	data, err = fs.ReadFile(sub, "INNER.TXT")
+	require.NoError(t, err)
+	assert.Equal(t, "inner!", string(data))
+}
+
+// TestFSPrefersRockRidgePrimaryOverJoliet covers the layout real mkisofs/
+// genisoimage/xorriso images with "-R -J" actually produce: Rock Ridge
+// SUSP data lives only on the primary tree's directory records, and the
+// Joliet tree carries none. FS must still surface the Rock Ridge mode
+// rather than silently falling back to the synthesized default just
+// because an SVD is present.
+func TestFSPrefersRockRidgePrimaryOverJoliet(t *testing.T) {
+	spSystemUse := susEntry("SP", 1, []byte{0xBE, 0xEF, 0})
+
+	pxPayload := make([]byte, 32)
+	WriteInt32LSBMSB(pxPayload[0:8], 0o100600)
+	WriteInt32LSBMSB(pxPayload[8:16], 1)
+	WriteInt32LSBMSB(pxPayload[16:24], 0)
+	WriteInt32LSBMSB(pxPayload[24:32], 0)
+	nmPayload := append([]byte{0}, []byte("file.txt")...)
+
+	var fileSystemUse []byte
+	fileSystemUse = append(fileSystemUse, susEntry("NM", 1, nmPayload)...)
+	fileSystemUse = append(fileSystemUse, susEntry("PX", 1, pxPayload)...)
+
+	primaryDot := DirectoryEntry{ExtentLocation: 19, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0}), SystemUse: spSystemUse}
+	primaryDotDot := DirectoryEntry{ExtentLocation: 19, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})}
+	primaryFile := DirectoryEntry{ExtentLocation: 21, ExtentLength: 5, FileFlags: 0, Identifier: "FILE.TXT;1", SystemUse: fileSystemUse}
+
+	jolietDot := DirectoryEntry{ExtentLocation: 20, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})}
+	jolietDotDot := DirectoryEntry{ExtentLocation: 20, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})}
+	jolietFile := DirectoryEntry{ExtentLocation: 21, ExtentLength: 5, FileFlags: 0, Identifier: string(marshalUTF16BE("file.txt;1"))}
+
+	img := buildTestISOWithJoliet(t, []DirectoryEntry{jolietDot, jolietDotDot, jolietFile}, map[int32][]byte{
+		21: []byte("hello"),
+	})
+
+	// buildTestISOWithJoliet's primary root carries only "." / "..";
+	// overwrite it with entries that include the Rock Ridge SUSP data.
+	primaryDirSector := img[19*int(sectorSize) : 20*int(sectorSize)]
+	for i := range primaryDirSector {
+		primaryDirSector[i] = 0
+	}
+	offset := 0
+	for _, de := range []DirectoryEntry{primaryDot, primaryDotDot, primaryFile} {
+		data, err := de.MarshalBinary()
+		require.NoError(t, err)
+		copy(primaryDirSector[offset:], data)
+		offset += len(data)
+	}
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	fsys := image.FS()
+
+	info, err := fs.Stat(fsys, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0o600), info.Mode().Perm())
+
+	data, err := fs.ReadFile(fsys, "file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestFSOpenSeeksAndReadsAt(t *testing.T) {
+	img := buildFSTestISO(t)
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	f, err := image.FS().Open("README.TXT")
+	require.NoError(t, err)
+	defer f.Close()
+
+	ra, ok := f.(io.ReaderAt)
+	require.True(t, ok)
+	buf := make([]byte, 3)
+	n, err := ra.ReadAt(buf, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "llo", string(buf[:n]))
+
+	seeker, ok := f.(io.Seeker)
+	require.True(t, ok)
+	_, err = seeker.Seek(1, io.SeekStart)
+	require.NoError(t, err)
+
+	rest, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "ello", string(rest))
+}
@@ -0,0 +1,125 @@
+// Package iso9660 reads (and, via the Writer type, writes) ISO9660
+// filesystem images, with optional support for the Joliet and Rock Ridge
+// extensions.
+package iso9660
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Image represents an open ISO9660 filesystem image.
+type Image struct {
+	reader io.ReaderAt
+
+	pvd *primaryVolumeDescriptor
+	svd *supplementaryVolumeDescriptor // non-nil when a Joliet SVD was found
+
+	rockRidgeChecked bool
+	rockRidge        bool
+
+	hasBootRecord  bool
+	bootCatalogLBA uint32
+}
+
+// OpenImage parses the volume descriptor set of the ISO9660 image backed
+// by r and returns a handle that can be used to walk its directory tree.
+func OpenImage(r io.ReaderAt) (*Image, error) {
+	if r == nil {
+		return nil, fmt.Errorf("iso9660: reader cannot be nil")
+	}
+
+	img := &Image{reader: r}
+
+	for sector := int64(systemAreaSectors); ; sector++ {
+		buf := make([]byte, sectorSize)
+		if _, err := r.ReadAt(buf, sector*sectorSize); err != nil {
+			return nil, fmt.Errorf("iso9660: reading volume descriptor at sector %d: %w", sector, err)
+		}
+
+		if string(buf[1:6]) != "CD001" {
+			return nil, fmt.Errorf("iso9660: invalid volume descriptor signature at sector %d", sector)
+		}
+
+		switch buf[0] {
+		case volumeTypeBootRecord:
+			if !img.hasBootRecord && strings.HasPrefix(string(buf[7:39]), elToritoBootSystemID) {
+				img.hasBootRecord = true
+				img.bootCatalogLBA = binary.LittleEndian.Uint32(buf[71:75])
+			}
+		case volumeTypePrimary:
+			if img.pvd == nil {
+				pvd, err := unmarshalPrimaryVolumeDescriptor(buf)
+				if err != nil {
+					return nil, err
+				}
+				img.pvd = pvd
+			}
+		case volumeTypeSupplementary:
+			svd, err := unmarshalSupplementaryVolumeDescriptor(buf)
+			if err != nil {
+				return nil, err
+			}
+			if svd != nil && img.svd == nil {
+				img.svd = svd
+			}
+		case volumeTypeTerminator:
+			if img.pvd == nil {
+				return nil, fmt.Errorf("iso9660: no primary volume descriptor found")
+			}
+			return img, nil
+		}
+	}
+}
+
+// RootDir returns the root directory of the image's primary (ISO9660)
+// directory tree.
+func (i *Image) RootDir() (*File, error) {
+	if i.pvd == nil {
+		return nil, fmt.Errorf("iso9660: image has no primary volume descriptor")
+	}
+	return newFile(i, i.pvd.rootDirectoryEntry, false), nil
+}
+
+// JolietRoot returns the root directory of the image's Joliet directory
+// tree, which carries long, Unicode file names in place of the 8.3
+// identifiers of the primary tree. It returns (nil, nil) when the image
+// has no Joliet Supplementary Volume Descriptor.
+func (i *Image) JolietRoot() (*File, error) {
+	if i.svd == nil {
+		return nil, nil
+	}
+	return newFile(i, i.svd.rootDirectoryEntry, true), nil
+}
+
+// readAt adapts i.reader to the readAtFunc signature used by the Rock
+// Ridge SUSP parser.
+func (i *Image) readAt(p []byte, off int64) (int, error) {
+	return i.reader.ReadAt(p, off)
+}
+
+// hasRockRidgeExtension reports whether the image's primary directory
+// tree carries Rock Ridge extensions, detected once by checking the root
+// directory's "." entry for an "SP" indicator and cached for reuse.
+func (i *Image) hasRockRidgeExtension() bool {
+	if i.rockRidgeChecked {
+		return i.rockRidge
+	}
+	i.rockRidgeChecked = true
+
+	sector := make([]byte, i.pvd.rootDirectoryEntry.ExtentLength)
+	if _, err := i.reader.ReadAt(sector, int64(i.pvd.rootDirectoryEntry.ExtentLocation)*sectorSize); err != nil {
+		return false
+	}
+
+	var dot DirectoryEntry
+	if err := dot.UnmarshalBinary(sector); err != nil {
+		return false
+	}
+
+	i.rockRidge = hasRockRidge(dot.SystemUse)
+
+	return i.rockRidge
+}
@@ -0,0 +1,76 @@
+package iso9660
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// jolietEscapeSequences maps the 3-byte escape sequence found at offset 88
+// of a Supplementary Volume Descriptor to the UCS-2 level it declares, as
+// defined by the Joliet specification. A Supplementary Volume Descriptor
+// whose escape sequence doesn't match one of these is not Joliet (it may,
+// for example, be an ISO/IEC 9660:1999 descriptor) and is ignored.
+var jolietEscapeSequences = map[string]int{
+	"%/@": 1,
+	"%/C": 2,
+	"%/E": 3,
+}
+
+// supplementaryVolumeDescriptor holds the fields of a Joliet Supplementary
+// Volume Descriptor that this package cares about.
+type supplementaryVolumeDescriptor struct {
+	VolumeIdentifier string
+	Level            int
+
+	rootDirectoryEntry DirectoryEntry
+}
+
+// unmarshalSupplementaryVolumeDescriptor parses buf as a Supplementary
+// Volume Descriptor. It returns (nil, nil) if the descriptor's escape
+// sequence doesn't identify it as Joliet.
+func unmarshalSupplementaryVolumeDescriptor(buf []byte) (*supplementaryVolumeDescriptor, error) {
+	if len(buf) < sectorSize {
+		return nil, fmt.Errorf("iso9660: supplementary volume descriptor sector too short: %d bytes", len(buf))
+	}
+
+	level, ok := jolietEscapeSequences[string(buf[88:91])]
+	if !ok {
+		return nil, nil
+	}
+
+	svd := &supplementaryVolumeDescriptor{
+		VolumeIdentifier: decodeUTF16BE(buf[40:72]),
+		Level:            level,
+	}
+
+	if err := svd.rootDirectoryEntry.UnmarshalBinary(buf[156:190]); err != nil {
+		return nil, fmt.Errorf("iso9660: parsing Joliet root directory entry: %w", err)
+	}
+
+	return svd, nil
+}
+
+// decodeUTF16BE decodes a big-endian UTF-16 byte slice, as used for every
+// Joliet string field, into a UTF-8 Go string. Trailing space padding
+// (used in fixed-width fields like the volume identifier) and a trailing
+// NUL are trimmed.
+func decodeUTF16BE(b []byte) string {
+	u16 := make([]uint16, len(b)/2)
+	for i := range u16 {
+		u16[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return strings.TrimRight(string(utf16.Decode(u16)), " \x00")
+}
+
+// encodeUTF16BE encodes s as big-endian UTF-16, the form every Joliet
+// string field requires.
+func encodeUTF16BE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, len(units)*2)
+	for i, u := range units {
+		out[2*i] = byte(u >> 8)
+		out[2*i+1] = byte(u)
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+//go:build !integration
+// +build !integration
+
+package iso9660
+
+import (
+	"bytes"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// marshalUTF16BE encodes s as big-endian UTF-16, the form Joliet requires
+// for every directory identifier and string field.
+func marshalUTF16BE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, len(units)*2)
+	for i, u := range units {
+		out[2*i] = byte(u >> 8)
+		out[2*i+1] = byte(u)
+	}
+	return out
+}
+
+// buildTestISOWithJoliet extends buildTestISO's layout with a Joliet
+// Supplementary Volume Descriptor and a second root directory extent
+// whose entries carry UTF-16BE identifiers:
+//
+//	sector 16: primary volume descriptor
+//	sector 17: Joliet supplementary volume descriptor
+//	sector 18: terminator volume descriptor
+//	sector 19: primary root directory extent
+//	sector 20: Joliet root directory extent
+//	sector 21+: file data
+func buildTestISOWithJoliet(t *testing.T, jolietEntries []DirectoryEntry, fileData map[int32][]byte) []byte {
+	t.Helper()
+
+	totalSectors := 22
+	for loc, data := range fileData {
+		needed := int(loc) + (len(data)+int(sectorSize)-1)/int(sectorSize) + 1
+		if needed > totalSectors {
+			totalSectors = needed
+		}
+	}
+
+	img := make([]byte, totalSectors*int(sectorSize))
+
+	rootDE := DirectoryEntry{
+		ExtentLocation: 19,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{0}),
+	}
+	rdeBytes, err := rootDE.MarshalBinary()
+	require.NoError(t, err)
+
+	pvdSector := img[16*sectorSize : 17*sectorSize]
+	pvdSector[0] = volumeTypePrimary
+	copy(pvdSector[1:6], "CD001")
+	pvdSector[6] = 1
+	copy(pvdSector[40:72], MarshalString("TESTISO", 32))
+	WriteInt32LSBMSB(pvdSector[80:88], int32(totalSectors))
+	WriteInt16LSBMSB(pvdSector[128:132], int16(sectorSize))
+	copy(pvdSector[156:190], rdeBytes)
+	pvdSector[881] = 1
+
+	jolietRootDE := DirectoryEntry{
+		ExtentLocation: 20,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{0}),
+	}
+	jrdeBytes, err := jolietRootDE.MarshalBinary()
+	require.NoError(t, err)
+
+	svdSector := img[17*sectorSize : 18*sectorSize]
+	svdSector[0] = volumeTypeSupplementary
+	copy(svdSector[1:6], "CD001")
+	svdSector[6] = 1
+	copy(svdSector[40:72], marshalUTF16BE("TESTISO"))
+	WriteInt32LSBMSB(svdSector[80:88], int32(totalSectors))
+	copy(svdSector[88:91], "%/C") // UCS-2 level 2
+	WriteInt16LSBMSB(svdSector[128:132], int16(sectorSize))
+	copy(svdSector[156:190], jrdeBytes)
+
+	termSector := img[18*sectorSize : 19*sectorSize]
+	termSector[0] = volumeTypeTerminator
+	copy(termSector[1:6], "CD001")
+	termSector[6] = 1
+
+	// Primary root directory: just "." and "..", no long names available.
+	primaryDirSector := img[19*sectorSize : 20*sectorSize]
+	offset := 0
+	for _, de := range []DirectoryEntry{
+		{ExtentLocation: 19, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})},
+		{ExtentLocation: 19, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})},
+	} {
+		data, err := de.MarshalBinary()
+		require.NoError(t, err)
+		copy(primaryDirSector[offset:], data)
+		offset += len(data)
+	}
+
+	jolietDirSector := img[20*sectorSize : 21*sectorSize]
+	offset = 0
+	for _, de := range jolietEntries {
+		data, err := de.MarshalBinary()
+		require.NoError(t, err)
+		copy(jolietDirSector[offset:], data)
+		offset += len(data)
+	}
+
+	for loc, data := range fileData {
+		copy(img[int(loc)*int(sectorSize):], data)
+	}
+
+	return img
+}
+
+func TestJolietLongNames(t *testing.T) {
+	dotEntry := DirectoryEntry{ExtentLocation: 20, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})}
+	dotDotEntry := DirectoryEntry{ExtentLocation: 20, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})}
+
+	longName := "A Very Long Filename With Spaces.txt"
+	fileEntry := DirectoryEntry{
+		ExtentLocation: 21,
+		ExtentLength:   5,
+		FileFlags:      0,
+		Identifier:     string(marshalUTF16BE(longName + ";1")),
+	}
+
+	img := buildTestISOWithJoliet(t, []DirectoryEntry{dotEntry, dotDotEntry, fileEntry}, map[int32][]byte{
+		21: []byte("hello"),
+	})
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	jolietRoot, err := image.JolietRoot()
+	require.NoError(t, err)
+	require.NotNil(t, jolietRoot)
+
+	children, err := jolietRoot.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+	assert.Equal(t, longName, children[0].Name())
+}
+
+func TestNoJolietWhenAbsent(t *testing.T) {
+	img := buildTestISO(t, []DirectoryEntry{
+		{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})},
+		{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})},
+	}, nil)
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	jolietRoot, err := image.JolietRoot()
+	require.NoError(t, err)
+	assert.Nil(t, jolietRoot)
+}
@@ -0,0 +1,273 @@
+package iso9660
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// susp magic bytes identifying an "SP" System Use Sharing Protocol
+// indicator entry, as defined by IEEE P1281 (SUSP) section 5.3.
+var suspMagic = [2]byte{0xBE, 0xEF}
+
+// rockRidgeInfo accumulates the Rock Ridge metadata parsed out of a
+// directory entry's system use area, across as many CE continuation areas
+// as necessary.
+type rockRidgeInfo struct {
+	name      string
+	hasName   bool
+	mode      os.FileMode
+	hasMode   bool
+	nlink     uint32
+	uid       uint32
+	gid       uint32
+	modTime   int64 // unix seconds; only used when hasMTime is set
+	hasMTime  bool
+	symlink   string
+	isSymlink bool
+	relocated bool   // CL: this entry is a placeholder for a directory moved elsewhere
+	parent    bool   // PL: ".." entry of a relocated directory, points back to the placeholder
+	relocLoc  uint32 // CL: extent location of the actual, relocated directory
+	hidden    bool   // RE: the real, relocated copy of a directory; omit from its physical parent's listing
+}
+
+// parseSystemUse walks the System Use entries in sys (the directory
+// entry's SystemUse field), following CE continuation records through
+// readAt, and folds any Rock Ridge (and plain SUSP) fields it recognizes
+// into info.
+func parseSystemUse(readAt readAtFunc, sys []byte, info *rockRidgeInfo) error {
+	for len(sys) >= 4 {
+		sig := string(sys[0:2])
+		entryLen := int(sys[2])
+		if entryLen < 4 || entryLen > len(sys) {
+			return fmt.Errorf("iso9660: invalid SUSP entry length %d for %q", entryLen, sig)
+		}
+		entry := sys[4:entryLen]
+
+		switch sig {
+		case "NM":
+			parseNM(entry, info)
+		case "PX":
+			parsePX(entry, info)
+		case "TF":
+			parseTF(entry, info)
+		case "SL":
+			parseSL(entry, info)
+		case "CL":
+			info.relocated = true
+			if len(entry) >= 8 {
+				info.relocLoc = uint32(ReadInt32LSBMSB(entry[0:8]))
+			}
+		case "PL":
+			info.parent = true
+		case "RE":
+			info.hidden = true
+		case "CE":
+			loc, off, length := parseCE(entry)
+			next := make([]byte, length)
+			if _, err := readAt(next, int64(loc)*sectorSize+int64(off)); err != nil {
+				return fmt.Errorf("iso9660: reading CE continuation area: %w", err)
+			}
+			if err := parseSystemUse(readAt, next, info); err != nil {
+				return err
+			}
+		}
+
+		sys = sys[entryLen:]
+	}
+
+	return nil
+}
+
+// readAtFunc adapts io.ReaderAt.ReadAt to the narrow interface needed by
+// parseSystemUse, so it can be unit tested without a full Image.
+type readAtFunc func(p []byte, off int64) (int, error)
+
+// nmFlag bits, SUSP "NM" System Use Entry, Rock Ridge section 4.1.4.
+const (
+	nmFlagContinue byte = 1 << 0
+	nmFlagCurrent  byte = 1 << 1
+	nmFlagParent   byte = 1 << 2
+)
+
+func parseNM(entry []byte, info *rockRidgeInfo) {
+	if len(entry) == 0 {
+		return
+	}
+	flags, content := entry[0], entry[1:]
+
+	switch {
+	case flags&nmFlagCurrent != 0:
+		info.name, info.hasName = ".", true
+		return
+	case flags&nmFlagParent != 0:
+		info.name, info.hasName = "..", true
+		return
+	}
+
+	if flags&nmFlagContinue != 0 {
+		info.name += string(content)
+	} else {
+		info.name = info.name + string(content)
+	}
+	info.hasName = true
+}
+
+func parsePX(entry []byte, info *rockRidgeInfo) {
+	if len(entry) < 32 {
+		return
+	}
+	info.mode = rockRidgeFileMode(uint32(ReadInt32LSBMSB(entry[0:8])))
+	info.hasMode = true
+	info.nlink = uint32(ReadInt32LSBMSB(entry[8:16]))
+	info.uid = uint32(ReadInt32LSBMSB(entry[16:24]))
+	info.gid = uint32(ReadInt32LSBMSB(entry[24:32]))
+}
+
+// rockRidgeFileMode translates the POSIX st_mode bits carried in a PX
+// entry into a Go os.FileMode.
+func rockRidgeFileMode(raw uint32) os.FileMode {
+	mode := os.FileMode(raw & 0o777)
+
+	switch raw & 0o170000 {
+	case 0o040000:
+		mode |= os.ModeDir
+	case 0o120000:
+		mode |= os.ModeSymlink
+	case 0o020000:
+		mode |= os.ModeCharDevice | os.ModeDevice
+	case 0o060000:
+		mode |= os.ModeDevice
+	case 0o010000:
+		mode |= os.ModeNamedPipe
+	case 0o140000:
+		mode |= os.ModeSocket
+	}
+
+	return mode
+}
+
+// tfFlag bits, SUSP "TF" System Use Entry, Rock Ridge section 4.1.6.
+const (
+	tfFlagCreation   byte = 1 << 0
+	tfFlagModify     byte = 1 << 1
+	tfFlagAccess     byte = 1 << 2
+	tfFlagAttributes byte = 1 << 3
+	tfFlagLongForm   byte = 1 << 7
+)
+
+func parseTF(entry []byte, info *rockRidgeInfo) {
+	if len(entry) == 0 {
+		return
+	}
+	flags := entry[0]
+	rest := entry[1:]
+
+	stampLen := 7
+	if flags&tfFlagLongForm != 0 {
+		stampLen = 17
+	}
+
+	// Timestamps are stored in the order: creation, modify, access,
+	// attributes - skip any that the flags say aren't present.
+	offset := 0
+	for _, present := range []struct {
+		flag byte
+		want bool
+	}{
+		{tfFlagCreation, false},
+		{tfFlagModify, true},
+		{tfFlagAccess, false},
+		{tfFlagAttributes, false},
+	} {
+		if flags&present.flag == 0 {
+			continue
+		}
+		if offset+stampLen > len(rest) {
+			return
+		}
+		if present.want {
+			var ts RecordingTimestamp
+			if stampLen == 7 {
+				if err := ts.UnmarshalBinary(rest[offset : offset+7]); err == nil {
+					info.modTime = ts.Time().Unix()
+					info.hasMTime = true
+				}
+			} else if t, err := time.Parse("20060102150405", string(rest[offset:offset+14])); err == nil {
+				// Long form: ASCII digits YYYYMMDDHHMMSS plus hundredths,
+				// then a signed offset from GMT in 15-minute intervals.
+				info.modTime = t.Add(-time.Duration(int8(rest[offset+16])) * 15 * time.Minute).Unix()
+				info.hasMTime = true
+			}
+		}
+		offset += stampLen
+	}
+}
+
+func parseSL(entry []byte, info *rockRidgeInfo) {
+	if len(entry) < 1 {
+		return
+	}
+	components := entry[1:]
+
+	var b strings.Builder
+	if info.isSymlink {
+		// Target continues from a previous SL record.
+		b.WriteString(info.symlink)
+	}
+
+	for len(components) >= 2 {
+		compFlags := components[0]
+		compLen := int(components[1])
+		if 2+compLen > len(components) {
+			break
+		}
+		content := components[2 : 2+compLen]
+
+		switch {
+		case compFlags&0x08 != 0: // root
+			b.WriteString("/")
+		case compFlags&0x02 != 0: // current
+			b.WriteString(".")
+		case compFlags&0x04 != 0: // parent
+			b.WriteString("..")
+		default:
+			b.Write(content)
+		}
+
+		components = components[2+compLen:]
+		if len(components) > 0 && compFlags&0x08 == 0 {
+			b.WriteString("/")
+		}
+	}
+
+	info.symlink = b.String()
+	info.isSymlink = true
+}
+
+// parseCE decodes a "CE" continuation area pointer: the block and offset
+// within it where the next System Use entries live, and their length.
+func parseCE(entry []byte) (block uint32, offset uint32, length uint32) {
+	if len(entry) < 24 {
+		return 0, 0, 0
+	}
+	return uint32(ReadInt32LSBMSB(entry[0:8])), uint32(ReadInt32LSBMSB(entry[8:16])), uint32(ReadInt32LSBMSB(entry[16:24]))
+}
+
+// hasRockRidge reports whether the root directory's "." entry carries an
+// "SP" System Use Sharing Protocol indicator, which Rock Ridge requires
+// every participating directory tree to start with.
+func hasRockRidge(sys []byte) bool {
+	for len(sys) >= 4 {
+		entryLen := int(sys[2])
+		if entryLen < 4 || entryLen > len(sys) {
+			return false
+		}
+		if string(sys[0:2]) == "SP" && entryLen >= 7 && sys[4] == suspMagic[0] && sys[5] == suspMagic[1] {
+			return true
+		}
+		sys = sys[entryLen:]
+	}
+	return false
+}
@@ -0,0 +1,278 @@
+//go:build !integration
+// +build !integration
+
+package iso9660
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// susEntry builds a raw SUSP system use entry: a 2-byte signature, a
+// 1-byte length covering the whole entry, a 1-byte version, and payload.
+func susEntry(sig string, version byte, payload []byte) []byte {
+	buf := make([]byte, 4+len(payload))
+	copy(buf[0:2], sig)
+	buf[2] = byte(len(buf))
+	buf[3] = version
+	copy(buf[4:], payload)
+	return buf
+}
+
+// slComponent builds a single "SL" path component record.
+func slComponent(flags byte, content string) []byte {
+	return append([]byte{flags, byte(len(content))}, content...)
+}
+
+func TestRockRidgeNameAndPermissions(t *testing.T) {
+	spSystemUse := susEntry("SP", 1, []byte{0xBE, 0xEF, 0})
+
+	dotEntry := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{0}),
+		SystemUse:      spSystemUse,
+	}
+	dotDotEntry := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{1}),
+	}
+
+	pxPayload := make([]byte, 32)
+	WriteInt32LSBMSB(pxPayload[0:8], 0o100644)
+	WriteInt32LSBMSB(pxPayload[8:16], 1)
+	WriteInt32LSBMSB(pxPayload[16:24], 1000)
+	WriteInt32LSBMSB(pxPayload[24:32], 1000)
+
+	nmPayload := append([]byte{0}, []byte("a-very-long-rock-ridge-name.txt")...)
+
+	var fileSystemUse []byte
+	fileSystemUse = append(fileSystemUse, susEntry("NM", 1, nmPayload)...)
+	fileSystemUse = append(fileSystemUse, susEntry("PX", 1, pxPayload)...)
+
+	fileEntry := DirectoryEntry{
+		ExtentLocation: 19,
+		ExtentLength:   5,
+		FileFlags:      0,
+		Identifier:     "SHORT.TXT;1",
+		SystemUse:      fileSystemUse,
+	}
+
+	img := buildTestISO(t, []DirectoryEntry{dotEntry, dotDotEntry, fileEntry}, map[int32][]byte{19: []byte("hello")})
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	root, err := image.RootDir()
+	require.NoError(t, err)
+
+	children, err := root.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+
+	f := children[0]
+	assert.Equal(t, "a-very-long-rock-ridge-name.txt", f.Name())
+	assert.Equal(t, os.FileMode(0o644), f.Mode().Perm())
+	assert.EqualValues(t, 1000, f.Uid())
+	assert.EqualValues(t, 1000, f.Gid())
+	assert.False(t, f.IsSymlink())
+}
+
+func TestRockRidgeSymlink(t *testing.T) {
+	spSystemUse := susEntry("SP", 1, []byte{0xBE, 0xEF, 0})
+
+	dotEntry := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{0}),
+		SystemUse:      spSystemUse,
+	}
+	dotDotEntry := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{1}),
+	}
+
+	slPayload := append([]byte{0}, slComponent(0, "target.txt")...)
+	linkEntry := DirectoryEntry{
+		ExtentLocation: 19,
+		ExtentLength:   0,
+		FileFlags:      0,
+		Identifier:     "LINK;1",
+		SystemUse:      susEntry("SL", 1, slPayload),
+	}
+
+	img := buildTestISO(t, []DirectoryEntry{dotEntry, dotDotEntry, linkEntry}, nil)
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	root, err := image.RootDir()
+	require.NoError(t, err)
+
+	children, err := root.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+
+	f := children[0]
+	assert.True(t, f.IsSymlink())
+
+	target, err := f.Readlink()
+	require.NoError(t, err)
+	assert.Equal(t, "target.txt", target)
+}
+
+// TestRockRidgeSymlinkAbsoluteTarget covers an SL target starting with a
+// ROOT component followed by real path segments, which must not double up
+// the leading slash (e.g. "usr"+"bin" after ROOT should read "/usr/bin",
+// not "//usr/bin").
+func TestRockRidgeSymlinkAbsoluteTarget(t *testing.T) {
+	spSystemUse := susEntry("SP", 1, []byte{0xBE, 0xEF, 0})
+
+	dotEntry := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{0}),
+		SystemUse:      spSystemUse,
+	}
+	dotDotEntry := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{1}),
+	}
+
+	var slPayload []byte
+	slPayload = append(slPayload, 0)
+	slPayload = append(slPayload, slComponent(0x08, "")...)
+	slPayload = append(slPayload, slComponent(0, "usr")...)
+	slPayload = append(slPayload, slComponent(0, "bin")...)
+
+	linkEntry := DirectoryEntry{
+		ExtentLocation: 19,
+		ExtentLength:   0,
+		FileFlags:      0,
+		Identifier:     "LINK;1",
+		SystemUse:      susEntry("SL", 1, slPayload),
+	}
+
+	img := buildTestISO(t, []DirectoryEntry{dotEntry, dotDotEntry, linkEntry}, nil)
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	root, err := image.RootDir()
+	require.NoError(t, err)
+
+	children, err := root.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+
+	f := children[0]
+	assert.True(t, f.IsSymlink())
+
+	target, err := f.Readlink()
+	require.NoError(t, err)
+	assert.Equal(t, "/usr/bin", target)
+}
+
+// TestRockRidgeRelocatedDirectoryLength covers a directory relocated via
+// Rock Ridge's "CL" entry whose real extent is larger than the stub
+// placeholder entry's declared length - the case "PL"/"RE" exist for,
+// since deep trees are exactly what tends to need relocating.
+func TestRockRidgeRelocatedDirectoryLength(t *testing.T) {
+	spSystemUse := susEntry("SP", 1, []byte{0xBE, 0xEF, 0})
+
+	dotEntry := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{0}),
+		SystemUse:      spSystemUse,
+	}
+	dotDotEntry := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize,
+		FileFlags:      dirFlagDir,
+		Identifier:     string([]byte{1}),
+	}
+
+	clPayload := make([]byte, 8)
+	WriteInt32LSBMSB(clPayload, 20)
+	placeholder := DirectoryEntry{
+		ExtentLocation: 18,
+		ExtentLength:   sectorSize, // wrong: the real directory spans two sectors
+		FileFlags:      dirFlagDir,
+		Identifier:     "DEEPDIR",
+		SystemUse:      susEntry("CL", 1, clPayload),
+	}
+
+	img := buildTestISO(t, []DirectoryEntry{dotEntry, dotDotEntry, placeholder}, map[int32][]byte{
+		20: buildRelocatedDirBytes(t),
+	})
+
+	image, err := OpenImage(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	root, err := image.RootDir()
+	require.NoError(t, err)
+
+	children, err := root.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+
+	deep := children[0]
+	assert.Equal(t, "DEEPDIR", deep.Name())
+	assert.EqualValues(t, 2*sectorSize, deep.de.ExtentLength) // corrected from the "." record, not the stub
+
+	deepChildren, err := deep.GetChildren()
+	require.NoError(t, err)
+
+	var names []string
+	for _, c := range deepChildren {
+		names = append(names, c.Name())
+	}
+	assert.Contains(t, names, "PAD00.TXT")
+	assert.Contains(t, names, "ZZLAST.TXT") // only reachable with the corrected, two-sector length
+}
+
+// buildRelocatedDirBytes builds the raw content of a directory that has
+// been relocated via Rock Ridge's "CL" mechanism: its own "." entry
+// correctly declares its real, two-sector length, and its last child sits
+// past the placeholder's (wrong, one-sector) declared length.
+func buildRelocatedDirBytes(t *testing.T) []byte {
+	t.Helper()
+
+	buf := make([]byte, 2*sectorSize)
+
+	entries := []DirectoryEntry{
+		{ExtentLocation: 20, ExtentLength: 2 * sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{0})},
+		{ExtentLocation: 18, ExtentLength: sectorSize, FileFlags: dirFlagDir, Identifier: string([]byte{1})},
+	}
+	for i := 0; i < 60; i++ {
+		entries = append(entries, DirectoryEntry{ExtentLocation: 30, ExtentLength: 1, Identifier: fmt.Sprintf("PAD%02d.TXT;1", i)})
+	}
+	entries = append(entries, DirectoryEntry{ExtentLocation: 31, ExtentLength: 1, Identifier: "ZZLAST.TXT;1"})
+
+	offset := 0
+	for _, de := range entries {
+		data, err := de.MarshalBinary()
+		require.NoError(t, err)
+		copy(buf[offset:], data)
+		offset += len(data)
+	}
+	require.Greater(t, offset, sectorSize, "test setup: final entry must land past the stub's declared single sector")
+
+	return buf
+}
@@ -0,0 +1,47 @@
+package iso9660
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordingTimestamp is the 7-byte recording date and time stored in a
+// directory record, as defined by ECMA-119 section 9.1.5.
+type RecordingTimestamp struct {
+	Years     uint8 // number of years since 1900
+	Month     uint8 // 1-12
+	Day       uint8 // 1-31
+	Hour      uint8 // 0-23
+	Minute    uint8 // 0-59
+	Second    uint8 // 0-59
+	GMTOffset int8  // offset from GMT, in 15-minute intervals
+}
+
+// MarshalBinary encodes the timestamp into its 7-byte on-disk form.
+func (t RecordingTimestamp) MarshalBinary() ([]byte, error) {
+	return []byte{t.Years, t.Month, t.Day, t.Hour, t.Minute, t.Second, byte(t.GMTOffset)}, nil
+}
+
+// UnmarshalBinary decodes a 7-byte recording date and time.
+func (t *RecordingTimestamp) UnmarshalBinary(data []byte) error {
+	if len(data) < 7 {
+		return fmt.Errorf("iso9660: recording timestamp too short: %d bytes", len(data))
+	}
+
+	t.Years = data[0]
+	t.Month = data[1]
+	t.Day = data[2]
+	t.Hour = data[3]
+	t.Minute = data[4]
+	t.Second = data[5]
+	t.GMTOffset = int8(data[6])
+
+	return nil
+}
+
+// Time converts the recording timestamp to a time.Time in the zone implied
+// by GMTOffset.
+func (t RecordingTimestamp) Time() time.Time {
+	loc := time.FixedZone("", int(t.GMTOffset)*15*60)
+	return time.Date(1900+int(t.Years), time.Month(t.Month), int(t.Day), int(t.Hour), int(t.Minute), int(t.Second), 0, loc)
+}
@@ -0,0 +1,57 @@
+package iso9660
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// MarshalString pads s with spaces (the ISO9660 filler byte) up to length,
+// truncating it if it is already longer.
+func MarshalString(s string, length int) []byte {
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = ' '
+	}
+
+	n := len(s)
+	if n > length {
+		n = length
+	}
+	copy(out, s[:n])
+
+	return out
+}
+
+// UnmarshalString trims the trailing space padding ISO9660 uses for
+// fixed-width string fields.
+func UnmarshalString(s string) string {
+	return strings.TrimRight(s, " ")
+}
+
+// WriteInt16LSBMSB writes v into the 4-byte buffer buf as a "both byte
+// order" 16-bit integer: little-endian first, then big-endian, as
+// required by ECMA-119 for fields such as the volume sequence number.
+func WriteInt16LSBMSB(buf []byte, v int16) {
+	binary.LittleEndian.PutUint16(buf[0:2], uint16(v))
+	binary.BigEndian.PutUint16(buf[2:4], uint16(v))
+}
+
+// WriteInt32LSBMSB writes v into the 8-byte buffer buf as a "both byte
+// order" 32-bit integer: little-endian first, then big-endian, as
+// required by ECMA-119 for fields such as the volume space size.
+func WriteInt32LSBMSB(buf []byte, v int32) {
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(v))
+	binary.BigEndian.PutUint32(buf[4:8], uint32(v))
+}
+
+// ReadInt16LSBMSB reads a "both byte order" 16-bit integer written by
+// WriteInt16LSBMSB, trusting the little-endian half.
+func ReadInt16LSBMSB(buf []byte) int16 {
+	return int16(binary.LittleEndian.Uint16(buf[0:2]))
+}
+
+// ReadInt32LSBMSB reads a "both byte order" 32-bit integer written by
+// WriteInt32LSBMSB, trusting the little-endian half.
+func ReadInt32LSBMSB(buf []byte) int32 {
+	return int32(binary.LittleEndian.Uint32(buf[0:4]))
+}
@@ -0,0 +1,33 @@
+package iso9660
+
+import "fmt"
+
+// primaryVolumeDescriptor holds the fields of the Primary Volume
+// Descriptor (ECMA-119 section 8.4) that this package cares about.
+type primaryVolumeDescriptor struct {
+	SystemIdentifier string
+	VolumeIdentifier string
+	VolumeSpaceSize  uint32
+	LogicalBlockSize uint16
+
+	rootDirectoryEntry DirectoryEntry
+}
+
+func unmarshalPrimaryVolumeDescriptor(buf []byte) (*primaryVolumeDescriptor, error) {
+	if len(buf) < sectorSize {
+		return nil, fmt.Errorf("iso9660: primary volume descriptor sector too short: %d bytes", len(buf))
+	}
+
+	pvd := &primaryVolumeDescriptor{
+		SystemIdentifier: UnmarshalString(string(buf[8:40])),
+		VolumeIdentifier: UnmarshalString(string(buf[40:72])),
+		VolumeSpaceSize:  uint32(ReadInt32LSBMSB(buf[80:88])),
+		LogicalBlockSize: uint16(ReadInt16LSBMSB(buf[128:130])),
+	}
+
+	if err := pvd.rootDirectoryEntry.UnmarshalBinary(buf[156:190]); err != nil {
+		return nil, fmt.Errorf("iso9660: parsing root directory entry: %w", err)
+	}
+
+	return pvd, nil
+}
@@ -0,0 +1,918 @@
+package iso9660
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// maxExtentBytes is the largest single extent ECMA-119 can describe (its
+// length fields are 32-bit). Files larger than this are split across
+// multiple directory records, chained via dirFlagMultiExtent, and rounded
+// down to a sector boundary so every extent but the last is a whole
+// number of sectors.
+const maxExtentBytes = 0xFFFFFFFF - (0xFFFFFFFF % sectorSize)
+
+// Writer builds a new ISO9660 image from a tree staged on an afero.Fs -
+// typically afero.NewMemMapFs() for small images, or afero.NewBasePathFs
+// over a scratch directory on disk for larger ones. Stage the tree using
+// the filesystem's own Create/Mkdir/MkdirAll/OpenFile/Chtimes/Chmod/
+// Remove/Rename methods (available via FS), then call WriteTo to render
+// it to an ISO9660 image.
+type Writer struct {
+	fs afero.Fs
+
+	// VolumeIdentifier is written into the Primary (and, when Joliet is
+	// enabled, Supplementary) Volume Descriptor. It defaults to
+	// "ISOIMAGE".
+	VolumeIdentifier string
+
+	// Joliet, when true, additionally emits a Joliet Supplementary Volume
+	// Descriptor and directory tree carrying long, Unicode file names.
+	Joliet bool
+
+	// RockRidge, when true, additionally emits Rock Ridge (SUSP) system
+	// use entries - NM, PX, TF and SL - recording each entry's original
+	// long name, POSIX mode, modification time and symlink target.
+	RockRidge bool
+}
+
+// NewWriter returns a Writer that stages its tree on fs.
+func NewWriter(fs afero.Fs) *Writer {
+	return &Writer{fs: fs, VolumeIdentifier: "ISOIMAGE"}
+}
+
+// FS returns the staging filesystem passed to NewWriter.
+func (w *Writer) FS() afero.Fs {
+	return w.fs
+}
+
+// writerNode is one file or directory of the tree staged on the Writer's
+// filesystem, carrying both the metadata read from that filesystem and
+// the layout this package computes for it.
+type writerNode struct {
+	name    string // original, possibly long, name as staged
+	isoName string // short ISO9660 identifier; files carry a ";1" suffix, directories none
+	isDir   bool
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+	uid     uint32
+	gid     uint32
+	srcPath string
+
+	isSymlink     bool
+	symlinkTarget string // only set when isSymlink
+
+	parent   *writerNode
+	children []*writerNode
+
+	pathTableNumber uint16
+
+	primaryLocation uint32
+	primaryLength   uint32 // directories only
+
+	jolietLocation uint32
+	jolietLength   uint32 // directories only
+
+	fileExtents []extentRun // files only; more than one element past maxExtentBytes
+}
+
+func (n *writerNode) dirLocation(joliet bool) (uint32, uint32) {
+	if joliet {
+		return n.jolietLocation, n.jolietLength
+	}
+	return n.primaryLocation, n.primaryLength
+}
+
+// WriteTo renders the staged tree as an ISO9660 image, streaming each
+// section to out as soon as its bytes are produced rather than
+// materializing the whole image in memory, and returns the number of
+// bytes written.
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	root, err := w.buildTree()
+	if err != nil {
+		return 0, err
+	}
+
+	order := assignPathTable(root)
+
+	for _, n := range order {
+		for _, c := range n.children {
+			if !c.isDir {
+				assignFileExtentSizes(c)
+			}
+		}
+	}
+
+	layout, err := w.layout(root, order)
+	if err != nil {
+		return 0, err
+	}
+
+	sw := &sectionWriter{out: out}
+
+	if err := sw.zero(int64(systemAreaSectors) * sectorSize); err != nil {
+		return sw.pos, err
+	}
+
+	if err := w.writeVolumeDescriptors(sw, root, layout); err != nil {
+		return sw.pos, err
+	}
+
+	if err := sw.writeSectors(buildPathTable(order, false, false)); err != nil {
+		return sw.pos, err
+	}
+	if err := sw.writeSectors(buildPathTable(order, false, true)); err != nil {
+		return sw.pos, err
+	}
+	if w.Joliet {
+		if err := sw.writeSectors(buildPathTable(order, true, false)); err != nil {
+			return sw.pos, err
+		}
+		if err := sw.writeSectors(buildPathTable(order, true, true)); err != nil {
+			return sw.pos, err
+		}
+	}
+
+	for _, n := range order {
+		if err := w.writeDirectory(sw, n, false); err != nil {
+			return sw.pos, err
+		}
+	}
+	if w.Joliet {
+		for _, n := range order {
+			if err := w.writeDirectory(sw, n, true); err != nil {
+				return sw.pos, err
+			}
+		}
+	}
+
+	if err := w.writeFiles(sw, order); err != nil {
+		return sw.pos, err
+	}
+
+	return sw.pos, nil
+}
+
+// sectionWriter wraps the io.Writer passed to WriteTo with a running byte
+// position, so every section of the image - volume descriptors, path
+// tables, directory extents, file content - can be streamed straight to
+// it in the same order layout assigns them sectors, instead of being
+// assembled into one in-memory image first.
+type sectionWriter struct {
+	out io.Writer
+	pos int64
+}
+
+// Write implements io.Writer, so a sectionWriter can itself be the
+// destination of an io.CopyN when streaming file content.
+func (sw *sectionWriter) Write(p []byte) (int, error) {
+	n, err := sw.out.Write(p)
+	sw.pos += int64(n)
+	return n, err
+}
+
+// zero writes n zero bytes.
+func (sw *sectionWriter) zero(n int64) error {
+	if n <= 0 {
+		return nil
+	}
+
+	z := make([]byte, sectorSize)
+	for n > 0 {
+		chunk := n
+		if chunk > int64(len(z)) {
+			chunk = int64(len(z))
+		}
+		if _, err := sw.Write(z[:chunk]); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+
+	return nil
+}
+
+// writeSectors writes data, then zero-pads it out to a whole number of
+// sectors, matching the space layout reserved for it.
+func (sw *sectionWriter) writeSectors(data []byte) error {
+	if _, err := sw.Write(data); err != nil {
+		return err
+	}
+	return sw.zero(int64(sectorsFor(uint32(len(data))))*sectorSize - int64(len(data)))
+}
+
+// buildTree walks the staged filesystem into an in-memory tree, sorted by
+// name at every level so it can drive both the ISO9660 path table and
+// directory record ordering.
+func (w *Writer) buildTree() (*writerNode, error) {
+	root := &writerNode{isDir: true, modTime: time.Now(), mode: os.ModeDir | 0o555}
+	nodes := map[string]*writerNode{"/": root}
+
+	err := afero.Walk(w.fs, "/", func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "/" {
+			return nil
+		}
+
+		clean := strings.TrimPrefix(p, "/")
+		parentPath := "/" + path.Dir(clean)
+		if parentPath == "/." {
+			parentPath = "/"
+		}
+
+		parent, ok := nodes[parentPath]
+		if !ok {
+			return fmt.Errorf("iso9660: walking staged tree: parent of %q not seen before it", p)
+		}
+
+		node := &writerNode{
+			name:    info.Name(),
+			isoName: isoShortName(info.Name(), info.IsDir()),
+			isDir:   info.IsDir(),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+			mode:    info.Mode(),
+			srcPath: p,
+			parent:  parent,
+		}
+		node.uid, node.gid, _ = fileOwnership(info)
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			if lr, ok := w.fs.(afero.LinkReader); ok {
+				target, err := lr.ReadlinkIfPossible(p)
+				if err != nil {
+					return fmt.Errorf("iso9660: reading symlink %s: %w", p, err)
+				}
+				node.isSymlink = true
+				node.symlinkTarget = target
+				node.size = 0
+			}
+		}
+
+		parent.children = append(parent.children, node)
+
+		if node.isDir {
+			nodes["/"+clean] = node
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("iso9660: walking staged tree: %w", err)
+	}
+
+	sortChildren(root)
+
+	return root, nil
+}
+
+func sortChildren(n *writerNode) {
+	sort.Slice(n.children, func(i, j int) bool { return n.children[i].name < n.children[j].name })
+	for _, c := range n.children {
+		if c.isDir {
+			sortChildren(c)
+		}
+	}
+}
+
+// assignPathTable numbers every directory in breadth-first, parent-before-
+// child order - the order ECMA-119 path tables require - and returns them
+// in that order, root first.
+func assignPathTable(root *writerNode) []*writerNode {
+	root.pathTableNumber = 1
+	order := []*writerNode{root}
+	queue := []*writerNode{root}
+	next := uint16(2)
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for _, c := range cur.children {
+			if !c.isDir {
+				continue
+			}
+			c.pathTableNumber = next
+			next++
+			order = append(order, c)
+			queue = append(queue, c)
+		}
+	}
+
+	return order
+}
+
+// assignFileExtentSizes splits c's content into one or more extentRuns,
+// sized from its staged length alone (locations are filled in later by
+// layout). Every file, including an empty one, ends up with at least one
+// run, so the directory record building code has a single path regardless
+// of size.
+func assignFileExtentSizes(c *writerNode) {
+	remaining := c.size
+
+	for remaining > 0 {
+		chunk := remaining
+		if chunk > maxExtentBytes {
+			chunk = maxExtentBytes
+		}
+		c.fileExtents = append(c.fileExtents, extentRun{length: uint32(chunk)})
+		remaining -= chunk
+	}
+
+	if len(c.fileExtents) == 0 {
+		c.fileExtents = append(c.fileExtents, extentRun{length: 0})
+	}
+}
+
+// isoShortName derives a level-1 ISO9660 identifier (upper-case, 8.3,
+// d-characters only) from a staged file or directory name. It is
+// deliberately simple - real long names survive via Joliet and/or Rock
+// Ridge's NM entries, so the short form only has to be unique enough to
+// round-trip the tree, not pretty.
+func isoShortName(name string, isDir bool) string {
+	name = strings.ToUpper(name)
+
+	if isDir {
+		if len(name) > 8 {
+			name = name[:8]
+		}
+		return sanitizeDChars(name)
+	}
+
+	base, ext := name, ""
+	if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
+		base, ext = name[:idx], name[idx+1:]
+	}
+	if len(base) > 8 {
+		base = base[:8]
+	}
+	if len(ext) > 3 {
+		ext = ext[:3]
+	}
+
+	out := sanitizeDChars(base)
+	if ext != "" {
+		out += "." + sanitizeDChars(ext)
+	}
+
+	return out + ";1"
+}
+
+func sanitizeDChars(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// writerLayout records where every fixed structure of the image - volume
+// descriptors and path tables - lands, once the size of each is known.
+// Per-node locations (directory and file extents) live on the nodes
+// themselves.
+type writerLayout struct {
+	totalSectors uint32
+
+	pathTableSize uint32
+	pathTableLLoc uint32
+	pathTableMLoc uint32
+
+	jolietPathTableSize uint32
+	jolietPathTableLLoc uint32
+	jolietPathTableMLoc uint32
+}
+
+// layout performs the first of the Writer's two passes: it sizes and
+// assigns sectors to every directory extent, in path-table order, then to
+// every file's extents, without touching any file content. The second
+// pass (writeDirectory/writeFiles) streams the actual bytes once every
+// location is known.
+func (w *Writer) layout(root *writerNode, order []*writerNode) (*writerLayout, error) {
+	l := &writerLayout{}
+
+	sector := uint32(systemAreaSectors)
+	sector++ // Primary Volume Descriptor
+	if w.Joliet {
+		sector++ // Joliet Supplementary Volume Descriptor
+	}
+	sector++ // volume descriptor set terminator
+
+	l.pathTableSize = pathTableByteSize(order, false)
+	l.pathTableLLoc = sector
+	sector += sectorsFor(l.pathTableSize)
+	l.pathTableMLoc = sector
+	sector += sectorsFor(l.pathTableSize)
+
+	if w.Joliet {
+		l.jolietPathTableSize = pathTableByteSize(order, true)
+		l.jolietPathTableLLoc = sector
+		sector += sectorsFor(l.jolietPathTableSize)
+		l.jolietPathTableMLoc = sector
+		sector += sectorsFor(l.jolietPathTableSize)
+	}
+
+	for _, n := range order {
+		entries, err := w.buildDirEntries(n, false)
+		if err != nil {
+			return nil, err
+		}
+		secs, err := sectorsForEntries(entries)
+		if err != nil {
+			return nil, err
+		}
+		n.primaryLocation = sector
+		n.primaryLength = secs * sectorSize
+		sector += secs
+	}
+
+	if w.Joliet {
+		for _, n := range order {
+			entries, err := w.buildDirEntries(n, true)
+			if err != nil {
+				return nil, err
+			}
+			secs, err := sectorsForEntries(entries)
+			if err != nil {
+				return nil, err
+			}
+			n.jolietLocation = sector
+			n.jolietLength = secs * sectorSize
+			sector += secs
+		}
+	}
+
+	for _, n := range order {
+		for _, c := range n.children {
+			if c.isDir {
+				continue
+			}
+			for i := range c.fileExtents {
+				c.fileExtents[i].location = sector
+				sector += sectorsFor(c.fileExtents[i].length)
+			}
+		}
+	}
+
+	l.totalSectors = sector
+
+	return l, nil
+}
+
+func sectorsFor(n uint32) uint32 {
+	return (n + sectorSize - 1) / sectorSize
+}
+
+// sectorsForEntries simulates the same sector-packing writeDirectory
+// later performs, so that layout's size estimate and the actual bytes
+// written always agree: a record is never split across a sector
+// boundary, so a record that wouldn't fit in what's left of the current
+// sector starts the next one instead.
+func sectorsForEntries(entries []DirectoryEntry) (uint32, error) {
+	sectorsUsed := uint32(1)
+	offset := 0
+
+	for _, de := range entries {
+		data, err := de.MarshalBinary()
+		if err != nil {
+			return 0, err
+		}
+		if offset+len(data) > sectorSize {
+			sectorsUsed++
+			offset = 0
+		}
+		offset += len(data)
+	}
+
+	return sectorsUsed, nil
+}
+
+// buildDirEntries returns the directory records - "." and ".." followed
+// by one entry per child - that make up n's extent. It is called both
+// during layout, to size the extent before any location is known, and
+// during rendering, once every location has been assigned; the DirectoryEntry
+// fields it produces are simply zero in the former case.
+func (w *Writer) buildDirEntries(n *writerNode, joliet bool) ([]DirectoryEntry, error) {
+	selfLoc, selfLen := n.dirLocation(joliet)
+	parentLoc, parentLen := selfLoc, selfLen
+	if n.parent != nil {
+		parentLoc, parentLen = n.parent.dirLocation(joliet)
+	}
+
+	entries := []DirectoryEntry{
+		{ExtentLocation: selfLoc, ExtentLength: selfLen, FileFlags: dirFlagDir, RecordingDateTime: recTimestamp(n.modTime), Identifier: string([]byte{0}), SystemUse: w.dotSystemUse(n)},
+		{ExtentLocation: parentLoc, ExtentLength: parentLen, FileFlags: dirFlagDir, RecordingDateTime: recTimestamp(n.modTime), Identifier: string([]byte{1})},
+	}
+
+	for _, c := range n.children {
+		childEntries, err := w.buildChildEntries(c, joliet)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, childEntries...)
+	}
+
+	return entries, nil
+}
+
+func (w *Writer) buildChildEntries(c *writerNode, joliet bool) ([]DirectoryEntry, error) {
+	ident := c.isoName
+	if joliet {
+		ident = c.name
+		if !c.isDir {
+			ident += ";1"
+		}
+		ident = string(encodeUTF16BE(ident))
+	}
+
+	var sysUse []byte
+	if w.RockRidge {
+		sysUse = w.nodeSystemUse(c)
+	}
+
+	if c.isDir {
+		loc, length := c.dirLocation(joliet)
+		return []DirectoryEntry{{
+			ExtentLocation: loc, ExtentLength: length, FileFlags: dirFlagDir,
+			RecordingDateTime: recTimestamp(c.modTime), Identifier: ident, SystemUse: sysUse,
+		}}, nil
+	}
+
+	entries := make([]DirectoryEntry, len(c.fileExtents))
+	for i, ext := range c.fileExtents {
+		flags := byte(0)
+		if i < len(c.fileExtents)-1 {
+			flags = dirFlagMultiExtent
+		}
+
+		var use []byte
+		if i == 0 {
+			use = sysUse
+		}
+
+		entries[i] = DirectoryEntry{
+			ExtentLocation: ext.location, ExtentLength: ext.length, FileFlags: flags,
+			RecordingDateTime: recTimestamp(c.modTime), Identifier: ident, SystemUse: use,
+		}
+	}
+
+	return entries, nil
+}
+
+// dotSystemUse returns the system use bytes for a directory's own "."
+// entry: the root's carries the "SP" indicator every Rock Ridge tree must
+// start with, and every directory's carries its own PX permissions.
+func (w *Writer) dotSystemUse(n *writerNode) []byte {
+	if !w.RockRidge {
+		return nil
+	}
+
+	var buf []byte
+	if n.parent == nil {
+		buf = append(buf, buildSUSPEntry("SP", []byte{suspMagic[0], suspMagic[1], 0})...)
+	}
+	buf = append(buf, pxEntry(n)...)
+
+	return buf
+}
+
+// nodeSystemUse returns the Rock Ridge system use bytes - NM, PX, TF and,
+// for symlinks, SL - describing a non-"."/".." child entry.
+func (w *Writer) nodeSystemUse(c *writerNode) []byte {
+	buf := buildSUSPEntry("NM", append([]byte{0}, []byte(c.name)...))
+	buf = append(buf, pxEntry(c)...)
+	buf = append(buf, tfEntry(c)...)
+	if c.isSymlink {
+		buf = append(buf, slEntry(c)...)
+	}
+	return buf
+}
+
+// pxEntry returns the Rock Ridge "PX" system use entry recording n's
+// POSIX permissions, link count, and owning uid/gid. Ownership is only
+// populated when the staging filesystem's FileInfo.Sys() exposes a
+// *syscall.Stat_t (true for afero.NewOsFs and afero.NewBasePathFs over a
+// real directory); trees staged on afero.NewMemMapFs have no concept of
+// ownership, so every entry's PX reports uid/gid 0.
+func pxEntry(n *writerNode) []byte {
+	payload := make([]byte, 32)
+
+	mode := uint32(n.mode.Perm())
+	switch {
+	case n.isDir:
+		mode |= 0o040000
+	case n.isSymlink:
+		mode |= 0o120000
+	default:
+		mode |= 0o100000
+	}
+
+	WriteInt32LSBMSB(payload[0:8], int32(mode))
+	WriteInt32LSBMSB(payload[8:16], 1) // nlink
+	WriteInt32LSBMSB(payload[16:24], int32(n.uid))
+	WriteInt32LSBMSB(payload[24:32], int32(n.gid))
+
+	return buildSUSPEntry("PX", payload)
+}
+
+func tfEntry(n *writerNode) []byte {
+	ts, _ := recTimestamp(n.modTime).MarshalBinary()
+	return buildSUSPEntry("TF", append([]byte{tfFlagModify}, ts...))
+}
+
+// slEntry returns the Rock Ridge "SL" system use entry recording c's
+// symbolic link target, split into path components as Rock Ridge section
+// 4.1.3 requires: one component record per path segment, with dedicated,
+// content-less flags for a leading "/", "." and "..".
+func slEntry(c *writerNode) []byte {
+	payload := []byte{0} // entry flags: not continued from an earlier SL
+
+	target := c.symlinkTarget
+	if strings.HasPrefix(target, "/") {
+		payload = append(payload, 0x08, 0)
+		target = strings.TrimPrefix(target, "/")
+	}
+
+	for _, part := range strings.Split(target, "/") {
+		switch part {
+		case "":
+			continue
+		case ".":
+			payload = append(payload, 0x02, 0)
+		case "..":
+			payload = append(payload, 0x04, 0)
+		default:
+			payload = append(payload, 0, byte(len(part)))
+			payload = append(payload, part...)
+		}
+	}
+
+	return buildSUSPEntry("SL", payload)
+}
+
+// buildSUSPEntry builds a raw SUSP system use entry: a 2-byte signature,
+// a 1-byte length covering the whole entry, a 1-byte version (always 1),
+// and payload.
+func buildSUSPEntry(sig string, payload []byte) []byte {
+	buf := make([]byte, 4+len(payload))
+	copy(buf[0:2], sig)
+	buf[2] = byte(len(buf))
+	buf[3] = 1
+	copy(buf[4:], payload)
+	return buf
+}
+
+// recTimestamp converts a time.Time to the 7-byte recording timestamp
+// directory records carry, preserving its original offset from UTC.
+func recTimestamp(t time.Time) RecordingTimestamp {
+	_, offsetSec := t.Zone()
+	return RecordingTimestamp{
+		Years:     uint8(t.Year() - 1900),
+		Month:     uint8(t.Month()),
+		Day:       uint8(t.Day()),
+		Hour:      uint8(t.Hour()),
+		Minute:    uint8(t.Minute()),
+		Second:    uint8(t.Second()),
+		GMTOffset: int8(offsetSec / (15 * 60)),
+	}
+}
+
+// pathTableIdentifier returns n's identifier as it appears in a path
+// table record: a single NUL byte for the root, its short ISO9660 name
+// otherwise, or the UTF-16BE long name when building the Joliet table.
+func pathTableIdentifier(n *writerNode, joliet bool) string {
+	if n.parent == nil {
+		return string([]byte{0})
+	}
+	if joliet {
+		return string(encodeUTF16BE(n.name))
+	}
+	return n.isoName
+}
+
+func pathTableByteSize(order []*writerNode, joliet bool) uint32 {
+	var size uint32
+	for _, n := range order {
+		idLen := len(pathTableIdentifier(n, joliet))
+		recLen := 8 + idLen
+		if recLen%2 != 0 {
+			recLen++
+		}
+		size += uint32(recLen)
+	}
+	return size
+}
+
+// buildPathTable renders one path table - L (little-endian) or M
+// (big-endian), primary or Joliet - as ECMA-119 section 9.4 defines it:
+// one record per directory, in the order directories are numbered,
+// recording each one's extent location and parent directory number.
+func buildPathTable(order []*writerNode, joliet, bigEndian bool) []byte {
+	var buf []byte
+
+	for _, n := range order {
+		ident := pathTableIdentifier(n, joliet)
+		idLen := len(ident)
+		pad := idLen % 2
+
+		rec := make([]byte, 8+idLen+pad)
+		rec[0] = byte(idLen)
+		rec[1] = 0
+
+		loc, _ := n.dirLocation(joliet)
+		parentNum := uint16(1)
+		if n.parent != nil {
+			parentNum = n.parent.pathTableNumber
+		}
+
+		if bigEndian {
+			binary.BigEndian.PutUint32(rec[2:6], loc)
+			binary.BigEndian.PutUint16(rec[6:8], parentNum)
+		} else {
+			binary.LittleEndian.PutUint32(rec[2:6], loc)
+			binary.LittleEndian.PutUint16(rec[6:8], parentNum)
+		}
+
+		copy(rec[8:8+idLen], ident)
+		buf = append(buf, rec...)
+	}
+
+	return buf
+}
+
+// writeDirectory renders n's directory record entries into a buffer the
+// same size as its already-assigned extent, packing them exactly as
+// sectorsForEntries predicted, and streams it to sw.
+func (w *Writer) writeDirectory(sw *sectionWriter, n *writerNode, joliet bool) error {
+	entries, err := w.buildDirEntries(n, joliet)
+	if err != nil {
+		return err
+	}
+
+	_, length := n.dirLocation(joliet)
+	buf := make([]byte, length)
+
+	sectorIdx := int64(0)
+	offset := 0
+
+	for _, de := range entries {
+		data, err := de.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if offset+len(data) > sectorSize {
+			sectorIdx++
+			offset = 0
+		}
+		copy(buf[sectorIdx*sectorSize+int64(offset):], data)
+		offset += len(data)
+	}
+
+	_, err = sw.Write(buf)
+	return err
+}
+
+// writeFiles streams every staged file's content into its assigned
+// extent(s), the second of the Writer's two passes.
+func (w *Writer) writeFiles(sw *sectionWriter, order []*writerNode) error {
+	for _, n := range order {
+		for _, c := range n.children {
+			if c.isDir {
+				continue
+			}
+			if err := w.writeFile(sw, c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *Writer) writeFile(sw *sectionWriter, c *writerNode) error {
+	if c.isSymlink {
+		// A symlink's target lives in its Rock Ridge "SL" entry; it has
+		// no content of its own to stream, just the zero-length extent
+		// assignFileExtentSizes already gave it.
+		return nil
+	}
+
+	f, err := w.fs.Open(c.srcPath)
+	if err != nil {
+		return fmt.Errorf("iso9660: opening %s: %w", c.srcPath, err)
+	}
+	defer f.Close()
+
+	for _, ext := range c.fileExtents {
+		n, err := io.CopyN(sw, f, int64(ext.length))
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("iso9660: reading %s: %w", c.srcPath, err)
+		}
+		if err := sw.zero(int64(ext.length) - n); err != nil {
+			return err
+		}
+		if err := sw.zero(int64(sectorsFor(ext.length))*sectorSize - int64(ext.length)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeVolumeDescriptors streams the Primary Volume Descriptor, the
+// optional Joliet Supplementary Volume Descriptor, and the volume
+// descriptor set terminator.
+func (w *Writer) writeVolumeDescriptors(sw *sectionWriter, root *writerNode, l *writerLayout) error {
+	pvd := make([]byte, sectorSize)
+	pvd[0] = volumeTypePrimary
+	copy(pvd[1:6], "CD001")
+	pvd[6] = 1
+	copy(pvd[40:72], MarshalString(w.VolumeIdentifier, 32))
+	WriteInt32LSBMSB(pvd[80:88], int32(l.totalSectors))
+	WriteInt16LSBMSB(pvd[120:124], 1)
+	WriteInt16LSBMSB(pvd[124:128], 1)
+	WriteInt16LSBMSB(pvd[128:132], int16(sectorSize))
+	WriteInt32LSBMSB(pvd[132:140], int32(l.pathTableSize))
+	binary.LittleEndian.PutUint32(pvd[140:144], l.pathTableLLoc)
+	binary.BigEndian.PutUint32(pvd[148:152], l.pathTableMLoc)
+
+	rootEntry := DirectoryEntry{
+		ExtentLocation: root.primaryLocation, ExtentLength: root.primaryLength,
+		FileFlags: dirFlagDir, RecordingDateTime: recTimestamp(root.modTime), Identifier: string([]byte{0}),
+	}
+	rootBytes, err := rootEntry.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	copy(pvd[156:190], rootBytes)
+	pvd[881] = 1
+
+	if _, err := sw.Write(pvd); err != nil {
+		return err
+	}
+
+	if w.Joliet {
+		svd := make([]byte, sectorSize)
+		svd[0] = volumeTypeSupplementary
+		copy(svd[1:6], "CD001")
+		svd[6] = 1
+		copy(svd[40:72], padUTF16(encodeUTF16BE(w.VolumeIdentifier), 32))
+		copy(svd[88:91], "%/C")
+		WriteInt32LSBMSB(svd[80:88], int32(l.totalSectors))
+		WriteInt16LSBMSB(svd[128:132], int16(sectorSize))
+		WriteInt32LSBMSB(svd[132:140], int32(l.jolietPathTableSize))
+		binary.LittleEndian.PutUint32(svd[140:144], l.jolietPathTableLLoc)
+		binary.BigEndian.PutUint32(svd[148:152], l.jolietPathTableMLoc)
+
+		jolietRoot := DirectoryEntry{
+			ExtentLocation: root.jolietLocation, ExtentLength: root.jolietLength,
+			FileFlags: dirFlagDir, RecordingDateTime: recTimestamp(root.modTime), Identifier: string([]byte{0}),
+		}
+		jolietRootBytes, err := jolietRoot.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		copy(svd[156:190], jolietRootBytes)
+
+		if _, err := sw.Write(svd); err != nil {
+			return err
+		}
+	}
+
+	term := make([]byte, sectorSize)
+	term[0] = volumeTypeTerminator
+	copy(term[1:6], "CD001")
+	term[6] = 1
+
+	_, err = sw.Write(term)
+	return err
+}
+
+// padUTF16 encodes b as a fixed-width, space-padded UTF-16BE field of the
+// given byte length, as ISO9660 requires for e.g. the volume identifier.
+func padUTF16(b []byte, length int) []byte {
+	out := make([]byte, length)
+	for i := 0; i+1 < length; i += 2 {
+		out[i], out[i+1] = 0x00, 0x20
+	}
+	n := len(b)
+	if n > length {
+		n = length
+	}
+	copy(out, b[:n])
+	return out
+}
@@ -0,0 +1,147 @@
+//go:build !integration
+// +build !integration
+
+package iso9660
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stageTestTree(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/README.TXT", []byte("hello"), 0o644))
+	require.NoError(t, fs.MkdirAll("/SUB", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/SUB/INNER.TXT", []byte("inner!"), 0o644))
+
+	return fs
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	w := NewWriter(stageTestTree(t))
+
+	var buf bytes.Buffer
+	n, err := w.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.EqualValues(t, buf.Len(), n)
+
+	image, err := OpenImage(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	root, err := image.RootDir()
+	require.NoError(t, err)
+
+	children, err := root.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, children, 2)
+
+	var readme, sub *File
+	for _, c := range children {
+		switch c.Name() {
+		case "README.TXT":
+			readme = c
+		case "SUB":
+			sub = c
+		}
+	}
+	require.NotNil(t, readme)
+	require.NotNil(t, sub)
+	assert.True(t, sub.IsDir())
+
+	data, err := io.ReadAll(readme.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	subChildren, err := sub.GetChildren()
+	require.NoError(t, err)
+	require.Len(t, subChildren, 1)
+	assert.Equal(t, "INNER.TXT", subChildren[0].Name())
+
+	data, err = io.ReadAll(subChildren[0].Reader())
+	require.NoError(t, err)
+	assert.Equal(t, "inner!", string(data))
+}
+
+func TestWriterJolietAndRockRidge(t *testing.T) {
+	w := NewWriter(stageTestTree(t))
+	w.Joliet = true
+	w.RockRidge = true
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	require.NoError(t, err)
+
+	image, err := OpenImage(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	jolietRoot, err := image.JolietRoot()
+	require.NoError(t, err)
+	require.NotNil(t, jolietRoot)
+
+	children, err := jolietRoot.GetChildren()
+	require.NoError(t, err)
+
+	var names []string
+	for _, c := range children {
+		names = append(names, c.Name())
+	}
+	assert.ElementsMatch(t, []string{"README.TXT", "SUB"}, names)
+
+	primaryRoot, err := image.RootDir()
+	require.NoError(t, err)
+	assert.True(t, image.hasRockRidgeExtension())
+
+	primaryChildren, err := primaryRoot.GetChildren()
+	require.NoError(t, err)
+	for _, c := range primaryChildren {
+		assert.NotZero(t, c.Mode())
+	}
+}
+
+// TestWriterSymlink stages a real symlink via an OS-backed afero.Fs -
+// afero.NewMemMapFs has no concept of symlinks - and checks it round-trips
+// as a Rock Ridge "SL" entry.
+func TestWriterSymlink(t *testing.T) {
+	dir := t.TempDir()
+	fs := afero.NewBasePathFs(afero.NewOsFs(), dir)
+
+	require.NoError(t, afero.WriteFile(fs, "/TARGET.TXT", []byte("hello"), 0o644))
+	require.NoError(t, os.Symlink("TARGET.TXT", dir+"/LINK.TXT"))
+
+	w := NewWriter(fs)
+	w.RockRidge = true
+
+	var buf bytes.Buffer
+	_, err := w.WriteTo(&buf)
+	require.NoError(t, err)
+
+	image, err := OpenImage(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	root, err := image.RootDir()
+	require.NoError(t, err)
+
+	children, err := root.GetChildren()
+	require.NoError(t, err)
+
+	var link *File
+	for _, c := range children {
+		if c.Name() == "LINK.TXT" {
+			link = c
+		}
+	}
+	require.NotNil(t, link)
+	assert.True(t, link.IsSymlink())
+
+	target, err := link.Readlink()
+	require.NoError(t, err)
+	assert.Equal(t, "TARGET.TXT", target)
+}
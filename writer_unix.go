@@ -0,0 +1,20 @@
+//go:build !windows
+
+package iso9660
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwnership extracts the uid/gid buildTree stages into a node's Rock
+// Ridge "PX" entry from info.Sys(), when the underlying filesystem
+// populates it with a *syscall.Stat_t (true for afero's OsFs and
+// BasePathFs over a real directory, not for afero.NewMemMapFs).
+func fileOwnership(info os.FileInfo) (uid, gid uint32, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return st.Uid, st.Gid, true
+}